@@ -1,9 +1,18 @@
 package db
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 
@@ -24,17 +33,421 @@ type DB interface {
 
 var ErrNotFound error = errors.New("not found")
 
+// beginner is implemented by sql.DB and sql.Conn
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// savepoints numbers the savepoints, since MySQL replaces a savepoint when
+// another one gets the same name
+var savepoints int64
+
+// Transaction calls fn in a transaction, see TransactionWithOptions
+func Transaction(ctx context.Context, db DB, fn func(tx DB) error) error {
+	return TransactionWithOptions(ctx, db, nil, fn)
+}
+
+// TransactionWithOptions calls fn in a transaction started with the options.
+// The transaction is committed if fn returns nil, and rolled back if it
+// returns an error or panics.
+//
+// A db that can't begin transactions, like a sql.Tx, is assumed to be in one
+// already. Then fn runs within a SAVEPOINT, so only its own changes are
+// rolled back, and options can't be given.
+func TransactionWithOptions(ctx context.Context, db DB, opts *sql.TxOptions, fn func(tx DB) error) error {
+	b, ok := db.(beginner)
+	if !ok {
+		if opts != nil && (opts.Isolation != sql.LevelDefault || opts.ReadOnly) {
+			return errors.New("can't set the options of a nested transaction")
+		}
+		return savepoint(ctx, db, fn)
+	}
+
+	tx, err := b.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// savepoint calls fn within a SAVEPOINT of the transaction db is in
+func savepoint(ctx context.Context, db DB, fn func(tx DB) error) error {
+	name := Dialect.QuoteIdentifier(fmt.Sprintf("savepoint_%d", atomic.AddInt64(&savepoints, 1)))
+	if _, err := db.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+	rollback := func() {
+		db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		db.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(db); err != nil {
+		rollback()
+		return err
+	}
+	_, err := db.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// Dialect is the SQL dialect queries are built for. Set it before running
+// any queries to use another database.
+var Dialect rel.Dialect = rel.SQLite
+
 type Relation interface {
+	// Average gives the average of the column's values, or 0 without any
+	Average(ctx context.Context, db DB, column string) (float64, error)
+
 	// Count ...
 	Count(ctx context.Context, db DB) (int64, error)
 
+	// CountBy counts the records for every value of the column. The values
+	// are of the types the driver returns, like int64 for integers, with
+	// []byte turned into string so they can be keys.
+	CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error)
+
 	// DeleteAll ...
 	DeleteAll(ctx context.Context, db DB) (int64, error)
 
+	// Maximum scans the largest of the column's values into dest, which
+	// has to take NULL when there are no values
+	Maximum(ctx context.Context, db DB, column string, dest interface{}) error
+
+	// Minimum scans the smallest of the column's values into dest, which
+	// has to take NULL when there are no values
+	Minimum(ctx context.Context, db DB, column string, dest interface{}) error
+
+	// Sum gives the sum of the column's values, or 0 without any
+	Sum(ctx context.Context, db DB, column string) (float64, error)
+
+	// SumInt gives the sum of an integer column's values, or 0 without any.
+	// Unlike Sum, it's exact beyond 2^53.
+	SumInt(ctx context.Context, db DB, column string) (int64, error)
+
 	// UpdateAll
 	UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error)
 }
 
+// Cursor is an opaque position in the pages of a relation, see Paginate. The
+// empty Cursor is the start of the first page.
+type Cursor string
+
+// cursorPosition is what a Cursor encodes: the values of the sort columns of
+// the record at the edge of a page, and whether the page is before it
+type cursorPosition struct {
+	Before bool                       `json:"b,omitempty"`
+	Values map[string]json.RawMessage `json:"v"`
+}
+
+func (p cursorPosition) encode() Cursor {
+	data, err := json.Marshal(p)
+	if err != nil {
+		panic(err)
+	}
+	return Cursor(base64.RawURLEncoding.EncodeToString(data))
+}
+
+func decodeCursor(c Cursor) (cursorPosition, error) {
+	var p cursorPosition
+	data, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err == nil {
+		err = json.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return p, errors.Errorf("invalid cursor %q", c)
+	}
+	return p, nil
+}
+
+// sortKey is a column a relation is ordered by
+type sortKey struct {
+	column     string
+	descending bool
+}
+
+// parseOrder finds the columns of the table the orders sort by. Orders given
+// as text need to be column names, optionally followed by ASC or DESC.
+func parseOrder(table string, orders []rel.Expr) ([]sortKey, error) {
+	var keys []sortKey
+	for _, order := range orders {
+		var text string
+		switch o := order.(type) {
+		case rel.Ascending:
+			if f, ok := o.Expr.(rel.Field); ok {
+				keys = append(keys, sortKey{column: f.Name})
+				continue
+			}
+		case rel.Descending:
+			if f, ok := o.Expr.(rel.Field); ok {
+				keys = append(keys, sortKey{column: f.Name, descending: true})
+				continue
+			}
+		case *rel.Literal:
+			text = o.Text
+		case rel.Literal:
+			text = o.Text
+		}
+
+		for _, term := range strings.Split(text, ",") {
+			words := strings.Fields(term)
+			if len(words) == 0 || len(words) > 2 {
+				return nil, errors.Errorf("can't paginate by %q", term)
+			}
+			key := sortKey{column: words[0]}
+			if len(words) == 2 {
+				switch strings.ToUpper(words[1]) {
+				case "ASC":
+				case "DESC":
+					key.descending = true
+				default:
+					return nil, errors.Errorf("can't paginate by %q", term)
+				}
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	for i := range keys {
+		keys[i].column = strings.TrimPrefix(keys[i].column, table+".")
+	}
+	return keys, nil
+}
+
+// appendSortKey adds the column to the keys, unless they already sort by it
+func appendSortKey(keys []sortKey, column string) []sortKey {
+	for _, key := range keys {
+		if key.column == column {
+			return keys
+		}
+	}
+	return append(keys, sortKey{column: column})
+}
+
+// seek matches the rows that come after the values in the order of the
+// keys, or before them
+func seek(table string, keys []sortKey, value func(column string) interface{}, before bool) rel.Expr {
+	var or rel.Or
+	for i, key := range keys {
+		var and rel.And
+		for _, prev := range keys[:i] {
+			and = append(and, rel.Equality{
+				Field: rel.Field{Name: table + "." + prev.column},
+				Value: rel.BindParam{Value: value(prev.column)},
+			})
+		}
+		field := rel.Field{Name: table + "." + key.column}
+		param := rel.BindParam{Value: value(key.column)}
+		if key.descending != before {
+			and = append(and, rel.LessThan{Field: field, Value: param})
+		} else {
+			and = append(and, rel.GreaterThan{Field: field, Value: param})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+// sameColumns is true when the lists have the same columns, in any order
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, column := range a {
+		if !containsColumn(b, column) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameJoins is true when the lists join the same tables in the same order
+func sameJoins(a, b []rel.Expr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// keyChunks splits the keys of a preload into chunks that fit in the bind
+// parameters of a statement
+func keyChunks(keys []interface{}) [][]interface{} {
+	size := Dialect.MaxParams()
+	var chunks [][]interface{}
+	for len(keys) > size {
+		chunks = append(chunks, keys[:size])
+		keys = keys[size:]
+	}
+	if len(keys) > 0 {
+		chunks = append(chunks, keys)
+	}
+	return chunks
+}
+
+// ValidationErrors maps column names to the problems with their values
+type ValidationErrors map[string][]string
+
+// Add adds a problem with the column's value
+func (e ValidationErrors) Add(column, message string) {
+	e[column] = append(e[column], message)
+}
+
+func (e ValidationErrors) Error() string {
+	columns := make([]string, 0, len(e))
+	for column := range e {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var messages []string
+	for _, column := range columns {
+		for _, message := range e[column] {
+			messages = append(messages, column+" "+message)
+		}
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Now returns the time Save sets timestamp columns to. Tests can replace it
+// to control the clock.
+var Now = time.Now
+
+// timeScanner scans a time into dest, which is a *time.Time, *sql.NullTime
+// or **time.Time. Besides time.Time values, it accepts the text SQLite stores
+// times as when the column isn't declared with a date or time type.
+type timeScanner struct {
+	dest interface{}
+}
+
+// timeFormats are the formats SQLite stores times as
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func (s timeScanner) Scan(value interface{}) error {
+	var t time.Time
+	switch v := value.(type) {
+	case nil:
+		switch dest := s.dest.(type) {
+		case *sql.NullTime:
+			*dest = sql.NullTime{}
+		case **time.Time:
+			*dest = nil
+		default:
+			return errors.New("can't scan NULL into time.Time")
+		}
+		return nil
+	case time.Time:
+		t = v
+	case string, []byte:
+		text := strings.TrimSpace(fmt.Sprintf("%s", v))
+		var err error
+		for _, format := range timeFormats {
+			if t, err = time.Parse(format, text); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return errors.Errorf("can't parse %q as a time", text)
+		}
+	default:
+		return errors.Errorf("can't scan %T into time.Time", value)
+	}
+
+	switch dest := s.dest.(type) {
+	case *time.Time:
+		*dest = t
+	case *sql.NullTime:
+		*dest = sql.NullTime{Time: t, Valid: true}
+	case **time.Time:
+		*dest = &t
+	}
+	return nil
+}
+
+// Records can implement these hooks to run code when they're saved or
+// deleted. Save calls BeforeSave, then BeforeCreate or BeforeUpdate, then
+// AfterCreate or AfterUpdate, then AfterSave. Delete calls BeforeDelete and
+// AfterDelete. The hooks get the DB passed to Save or Delete, so they're part
+// of its transaction. An error from a Before hook aborts the operation and is
+// returned, an error from an After hook is returned after the record has been
+// written.
+
+// BeforeSaveHook is implemented by a record to run code before it is created or updated
+type BeforeSaveHook interface {
+	BeforeSave(ctx context.Context, db DB) error
+}
+
+// BeforeCreateHook is implemented by a record to run code before it is inserted
+type BeforeCreateHook interface {
+	BeforeCreate(ctx context.Context, db DB) error
+}
+
+// AfterCreateHook is implemented by a record to run code after it is inserted
+type AfterCreateHook interface {
+	AfterCreate(ctx context.Context, db DB) error
+}
+
+// BeforeUpdateHook is implemented by a record to run code before it is updated
+type BeforeUpdateHook interface {
+	BeforeUpdate(ctx context.Context, db DB) error
+}
+
+// AfterUpdateHook is implemented by a record to run code after it is updated
+type AfterUpdateHook interface {
+	AfterUpdate(ctx context.Context, db DB) error
+}
+
+// AfterSaveHook is implemented by a record to run code after it is created or updated
+type AfterSaveHook interface {
+	AfterSave(ctx context.Context, db DB) error
+}
+
+// BeforeDeleteHook is implemented by a record to run code before it is deleted
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context, db DB) error
+}
+
+// AfterDeleteHook is implemented by a record to run code after it is deleted
+type AfterDeleteHook interface {
+	AfterDelete(ctx context.Context, db DB) error
+}
+
 type User struct {
 	// ID ...
 	ID int64
@@ -45,6 +458,12 @@ type User struct {
 	// LastName ...
 	LastName string
 
+	// Email ...
+	Email sql.NullString
+
+	// Avatar ...
+	Avatar []byte
+
 	// If true, then this record exists in the DB
 	persisted bool
 	deleted   bool
@@ -58,6 +477,12 @@ type User struct {
 
 		// LastName ...
 		LastName string
+
+		// Email ...
+		Email sql.NullString
+
+		// Avatar ...
+		Avatar []byte
 	}
 
 	associations struct {
@@ -85,7 +510,7 @@ type UserHasManyPostsCollection interface {
 type userHasManyPostsCollection User
 
 func (o *userHasManyPostsCollection) relation() PostRelation {
-	return Posts().WhereEq("user_id", o.ID)
+	return Posts().WhereEq("posts.user_id", o.ID)
 }
 
 func (o *userHasManyPostsCollection) Loaded() bool {
@@ -97,14 +522,38 @@ func (o *userHasManyPostsCollection) Reset() {
 	o.associations.Posts.loaded = false
 }
 
+func (o *userHasManyPostsCollection) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return o.relation().Average(ctx, db, column)
+}
+
 func (o *userHasManyPostsCollection) Count(ctx context.Context, db DB) (int64, error) {
 	return o.relation().Count(ctx, db)
 }
 
+func (o *userHasManyPostsCollection) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return o.relation().CountBy(ctx, db, column)
+}
+
 func (o *userHasManyPostsCollection) DeleteAll(ctx context.Context, db DB) (int64, error) {
 	return o.relation().DeleteAll(ctx, db)
 }
 
+func (o *userHasManyPostsCollection) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return o.relation().Maximum(ctx, db, column, dest)
+}
+
+func (o *userHasManyPostsCollection) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return o.relation().Minimum(ctx, db, column, dest)
+}
+
+func (o *userHasManyPostsCollection) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return o.relation().Sum(ctx, db, column)
+}
+
+func (o *userHasManyPostsCollection) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return o.relation().SumInt(ctx, db, column)
+}
+
 func (o *userHasManyPostsCollection) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
 	return o.relation().UpdateAll(ctx, db, query, args...)
 }
@@ -125,6 +574,10 @@ func (o *userHasManyPostsCollection) All(ctx context.Context, db DB) ([]*Post, e
 	return records, nil
 }
 
+func (o *userHasManyPostsCollection) Each(ctx context.Context, db DB, fn func(*Post) error) error {
+	return o.relation().Each(ctx, db, fn)
+}
+
 func (o *userHasManyPostsCollection) Find(ctx context.Context, db DB, id int64) (*Post, error) {
 	return o.relation().Find(ctx, db, id)
 }
@@ -133,10 +586,18 @@ func (o *userHasManyPostsCollection) FindBy(ctx context.Context, db DB, query st
 	return o.relation().FindBy(ctx, db, query, args...)
 }
 
+func (o *userHasManyPostsCollection) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Post) error) error {
+	return o.relation().FindInBatches(ctx, db, batchSize, fn)
+}
+
 func (o *userHasManyPostsCollection) First(ctx context.Context, db DB) (*Post, error) {
 	return o.relation().First(ctx, db)
 }
 
+func (o *userHasManyPostsCollection) Joins(associations ...string) PostRelation {
+	return o.relation().Joins(associations...)
+}
+
 func (o *userHasManyPostsCollection) Last(ctx context.Context, db DB) (*Post, error) {
 	return o.relation().Last(ctx, db)
 }
@@ -149,14 +610,30 @@ func (o *userHasManyPostsCollection) New() *Post {
 	return o.relation().New()
 }
 
+func (o *userHasManyPostsCollection) Not(value interface{}, args ...interface{}) PostRelation {
+	return o.relation().Not(value, args...)
+}
+
 func (o *userHasManyPostsCollection) Offset(offset int64) PostRelation {
 	return o.relation().Offset(offset)
 }
 
+func (o *userHasManyPostsCollection) Or(other PostRelation) PostRelation {
+	return o.relation().Or(other)
+}
+
 func (o *userHasManyPostsCollection) Order(query string, args ...string) PostRelation {
 	return o.relation().Order(query, args...)
 }
 
+func (o *userHasManyPostsCollection) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostPage, error) {
+	return o.relation().Paginate(ctx, db, cursor, pageSize)
+}
+
+func (o *userHasManyPostsCollection) Preload(associations ...string) PostRelation {
+	return o.relation().Preload(associations...)
+}
+
 func (o *userHasManyPostsCollection) Select(fields ...string) PostRelation {
 	return o.relation().Select(fields...)
 }
@@ -169,24 +646,60 @@ func (o *userHasManyPostsCollection) Where(value interface{}, args ...interface{
 	return o.relation().Where(value, args...)
 }
 
+func (o *userHasManyPostsCollection) WhereBetween(field string, low, high interface{}) PostRelation {
+	return o.relation().WhereBetween(field, low, high)
+}
+
 func (o *userHasManyPostsCollection) WhereEq(field string, value interface{}) PostRelation {
 	return o.relation().WhereEq(field, value)
 }
 
+func (o *userHasManyPostsCollection) WhereIn(field string, values ...interface{}) PostRelation {
+	return o.relation().WhereIn(field, values...)
+}
+
+func (o *userHasManyPostsCollection) WhereLike(field, pattern string) PostRelation {
+	return o.relation().WhereLike(field, pattern)
+}
+
+func (o *userHasManyPostsCollection) WhereNot(field string, value interface{}) PostRelation {
+	return o.relation().WhereNot(field, value)
+}
+
+func (o *userHasManyPostsCollection) whereExpr() rel.Expr {
+	return o.relation().whereExpr()
+}
+
+func (o *userHasManyPostsCollection) joinExprs() []rel.Expr {
+	return o.relation().joinExprs()
+}
+
 func (o *User) Save(ctx context.Context, db DB) error {
 	if o.deleted {
 		return fmt.Errorf("record deleted")
 	}
 
+	if err := o.Validate(ctx, db); err != nil {
+		return err
+	}
+
+	if hook, ok := interface{}(o).(BeforeSaveHook); ok {
+		if err := hook.BeforeSave(ctx, db); err != nil {
+			return err
+		}
+	}
+
 	if o.persisted {
+		if hook, ok := interface{}(o).(BeforeUpdateHook); ok {
+			if err := hook.BeforeUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+
 		stmt := &rel.UpdateStatement{
-			Table: "users",
-			Wheres: []rel.Expr{
-				rel.Assignment{
-					Field: rel.Field{"id"},
-					Value: rel.BindParam{Value: o.old.ID},
-				},
-			},
+			Dialect: Dialect,
+			Table:   "users",
+			Wheres:  o.keyWheres(),
 		}
 
 		if o.ID != o.old.ID {
@@ -216,14 +729,59 @@ func (o *User) Save(ctx context.Context, db DB) error {
 			})
 		}
 
-		query, values := stmt.Build()
-		_, err := db.ExecContext(ctx, query, values...)
-		if err != nil {
-			return errors.Wrapf(err, "executing %q", query)
+		if o.Email != o.old.Email {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"email"},
+				Value: &rel.BindParam{
+					Value: o.Email,
+				},
+			})
+		}
+
+		if (o.Avatar == nil) != (o.old.Avatar == nil) || !bytes.Equal(o.Avatar, o.old.Avatar) {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"avatar"},
+				Value: &rel.BindParam{
+					Value: o.Avatar,
+				},
+			})
+		}
+
+		// There's nothing to write when no field changed
+		if len(stmt.Values) > 0 {
+			if Dialect.Returning() {
+				stmt.Returning = userColumns
+			}
+			query, values := stmt.Build()
+			if Dialect.Returning() {
+				if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+				o.saved()
+				if err := o.reload(ctx, db); err != nil {
+					return err
+				}
+			}
+		}
+		if hook, ok := interface{}(o).(AfterUpdateHook); ok {
+			if err := hook.AfterUpdate(ctx, db); err != nil {
+				return err
+			}
 		}
 	} else {
+		if hook, ok := interface{}(o).(BeforeCreateHook); ok {
+			if err := hook.BeforeCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+
 		stmt := &rel.InsertStatement{
-			Table: "users",
+			Dialect: Dialect,
+			Table:   "users",
 		}
 
 		if o.ID != 0 {
@@ -240,55 +798,347 @@ func (o *User) Save(ctx context.Context, db DB) error {
 		stmt.Values = append(stmt.Values, &rel.BindParam{
 			Value: o.LastName,
 		})
+		stmt.Columns = append(stmt.Columns, "email")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.Email,
+		})
+		stmt.Columns = append(stmt.Columns, "avatar")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.Avatar,
+		})
 
-		query, values := stmt.Build()
-		res, err := db.ExecContext(ctx, query, values...)
-		if err != nil {
-			return errors.Wrapf(err, "executing %q", query)
+		if Dialect.Returning() {
+			stmt.Returning = userColumns
 		}
-		o.persisted = true
-
-		if o.ID == 0 {
-			o.ID, err = res.LastInsertId()
+		query, values := stmt.Build()
+		if Dialect.Returning() {
+			if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+		} else {
+			res, err := db.ExecContext(ctx, query, values...)
 			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+
+			if o.ID == 0 {
+				o.ID, err = res.LastInsertId()
+				if err != nil {
+					return err
+				}
+			}
+			o.saved()
+			if err := o.reload(ctx, db); err != nil {
+				return err
+			}
+		}
+		if hook, ok := interface{}(o).(AfterCreateHook); ok {
+			if err := hook.AfterCreate(ctx, db); err != nil {
 				return err
 			}
 		}
 	}
 
-	o.old.ID = o.ID
-	o.old.FirstName = o.FirstName
-	o.old.LastName = o.LastName
-
+	if hook, ok := interface{}(o).(AfterSaveHook); ok {
+		if err := hook.AfterSave(ctx, db); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (o *User) Delete(ctx context.Context, db DB) error {
-	_, err := Users().WhereEq("id", o.ID).DeleteAll(ctx, db)
-	if err != nil {
-		return err
+// Upsert inserts the record, or updates the row that has the same values for
+// the conflict columns, see UpsertAll
+func (o *User) Upsert(ctx context.Context, db DB, conflictColumns ...string) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
 	}
-	o.deleted = true
-	return err
+	return Users().UpsertAll(ctx, db, []*User{o}, conflictColumns...)
 }
 
-func (o *User) fieldPointerForColumn(column string) interface{} {
-	switch column {
-	case "id":
-		return &o.ID
-	case "first_name":
-		return &o.FirstName
-	case "last_name":
-		return &o.LastName
-	default:
-		return nil
-	}
-}
+var userEmailFormat = regexp.MustCompile("^[^@\\s]+@[^@\\s]+$")
 
-func (o *User) pointersForFields(fields []string) ([]interface{}, error) {
-	pointers := make([]interface{}, len(fields))
-	for i, field := range fields {
-		ptr := o.fieldPointerForColumn(field)
+// Validate checks the values against the validations in the schema. It
+// returns ValidationErrors when they don't pass.
+func (o *User) Validate(ctx context.Context, db DB) error {
+	errs := ValidationErrors{}
+
+	if o.Email.Valid {
+		v := o.Email.String
+		if !userEmailFormat.MatchString(v) {
+			errs.Add("email", "is invalid")
+		}
+		if !o.persisted || o.Email != o.old.Email {
+			q := Users().WhereEq("users.email", v)
+			if o.persisted {
+				q = q.Not(rel.And(o.keyWheres()))
+			}
+			count, err := q.Count(ctx, db)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				errs.Add("email", "has already been taken")
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// IsPersisted is true once the record has been loaded or saved
+func (o *User) IsPersisted() bool {
+	return o.persisted
+}
+
+// IsDeleted is true once the record has been deleted
+func (o *User) IsDeleted() bool {
+	return o.deleted
+}
+
+// IsChanged is true if any of the fields differ from the row the record was
+// loaded or last saved as. For new records, that's any field that isn't zero.
+func (o *User) IsChanged() bool {
+	return o.ID != o.old.ID ||
+		o.FirstName != o.old.FirstName ||
+		o.LastName != o.old.LastName ||
+		o.Email != o.old.Email ||
+		(o.Avatar == nil) != (o.old.Avatar == nil) || !bytes.Equal(o.Avatar, o.old.Avatar)
+}
+
+// ChangedColumns returns the columns of the fields that changed, in the order
+// of the table
+func (o *User) ChangedColumns() []string {
+	var columns []string
+	if o.ID != o.old.ID {
+		columns = append(columns, "id")
+	}
+	if o.FirstName != o.old.FirstName {
+		columns = append(columns, "first_name")
+	}
+	if o.LastName != o.old.LastName {
+		columns = append(columns, "last_name")
+	}
+	if o.Email != o.old.Email {
+		columns = append(columns, "email")
+	}
+	if (o.Avatar == nil) != (o.old.Avatar == nil) || !bytes.Equal(o.Avatar, o.old.Avatar) {
+		columns = append(columns, "avatar")
+	}
+	return columns
+}
+
+// Changes returns the old and new value of the changed fields by column
+func (o *User) Changes() map[string][2]interface{} {
+	changes := map[string][2]interface{}{}
+	if o.ID != o.old.ID {
+		changes["id"] = [2]interface{}{o.old.ID, o.ID}
+	}
+	if o.FirstName != o.old.FirstName {
+		changes["first_name"] = [2]interface{}{o.old.FirstName, o.FirstName}
+	}
+	if o.LastName != o.old.LastName {
+		changes["last_name"] = [2]interface{}{o.old.LastName, o.LastName}
+	}
+	if o.Email != o.old.Email {
+		changes["email"] = [2]interface{}{o.old.Email, o.Email}
+	}
+	if (o.Avatar == nil) != (o.old.Avatar == nil) || !bytes.Equal(o.Avatar, o.old.Avatar) {
+		changes["avatar"] = [2]interface{}{o.old.Avatar, o.Avatar}
+	}
+	return changes
+}
+
+// Restore reverts the fields to the row the record was loaded or last saved
+// as, undoing the changes
+func (o *User) Restore() {
+	o.ID = o.old.ID
+	o.FirstName = o.old.FirstName
+	o.LastName = o.old.LastName
+	o.Email = o.old.Email
+	o.Avatar = append(o.old.Avatar[:0:0], o.old.Avatar...)
+}
+
+// Reload reads the record's row again and resets the association caches.
+// It returns ErrNotFound if the row no longer exists.
+func (o *User) Reload(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	if !o.persisted {
+		return fmt.Errorf("record not persisted")
+	}
+	if err := o.reload(ctx, db); err != nil {
+		return err
+	}
+	var reset User
+	o.associations = reset.associations
+	return nil
+}
+
+// saved records that the fields match the row
+func (o *User) saved() {
+	o.old.ID = o.ID
+	o.old.FirstName = o.FirstName
+	o.old.LastName = o.LastName
+	o.old.Email = o.Email
+	o.old.Avatar = append(o.Avatar[:0:0], o.Avatar...)
+}
+
+// userColumns are the columns Save reads back after writing a row
+var userColumns = []string{"id", "first_name", "last_name", "email", "avatar"}
+
+// scan reads the row's columns into the record, which then matches it
+func (o *User) scan(row *sql.Row) error {
+	ptrs, err := o.pointersForFields(userColumns)
+	if err != nil {
+		return err
+	}
+	if err := row.Scan(ptrs...); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	o.saved()
+	return nil
+}
+
+// reload selects the record's row again, to see the values the database set
+// when the dialect can't return them from the insert or update
+func (o *User) reload(ctx context.Context, db DB) error {
+	stmt := &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: (&userRelation{}).columns(),
+		Table:   "users",
+		Wheres:  o.keyWheres(),
+	}
+	query, values := stmt.Build()
+	return o.scan(db.QueryRowContext(ctx, query, values...))
+}
+
+func (o *User) Delete(ctx context.Context, db DB) error {
+	if hook, ok := interface{}(o).(BeforeDeleteHook); ok {
+		if err := hook.BeforeDelete(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	_, err := (&userRelation{whereClause: o.keyWheres()}).DeleteAll(ctx, db)
+	if err != nil {
+		return err
+	}
+	o.deleted = true
+	if hook, ok := interface{}(o).(AfterDeleteHook); ok {
+		if err := hook.AfterDelete(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyWheres matches the record's row. Once persisted, that's by the key it
+// was loaded or last saved with, so changes to the key are saved too.
+func (o *User) keyWheres() []rel.Expr {
+	if !o.persisted {
+		return []rel.Expr{
+			rel.Equality{
+				Field: rel.Field{"id"},
+				Value: rel.BindParam{Value: o.ID},
+			},
+		}
+	}
+
+	return []rel.Expr{
+		rel.Equality{
+			Field: rel.Field{"id"},
+			Value: rel.BindParam{Value: o.old.ID},
+		},
+	}
+}
+
+// afterKey matches the rows ordered after the record by primary key
+func (o *User) afterKey() rel.Expr {
+	return rel.Or{
+		rel.And{
+			rel.GreaterThan{
+				Field: rel.Field{"users.id"},
+				Value: rel.BindParam{Value: o.ID},
+			},
+		},
+	}
+}
+
+// cursor is the position of the record in the order of the keys
+func (o *User) cursor(keys []sortKey, before bool) Cursor {
+	position := cursorPosition{Before: before, Values: make(map[string]json.RawMessage, len(keys))}
+	for _, key := range keys {
+		value, err := json.Marshal(o.valueForColumn(key.column))
+		if err != nil {
+			panic(err)
+		}
+		position.Values[key.column] = value
+	}
+	return position.encode()
+}
+
+func (o *User) fieldPointerForColumn(column string) interface{} {
+	switch column {
+	case "id":
+		return &o.ID
+	case "first_name":
+		return &o.FirstName
+	case "last_name":
+		return &o.LastName
+	case "email":
+		return &o.Email
+	case "avatar":
+		return &o.Avatar
+	default:
+		return nil
+	}
+}
+
+// sortable is true for the columns Paginate can order by, which are the ones
+// that can't be NULL
+func (o *User) sortable(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "first_name":
+		return true
+	case "last_name":
+		return true
+	default:
+		return false
+	}
+}
+
+func (o *User) valueForColumn(column string) interface{} {
+	switch column {
+	case "id":
+		return o.ID
+	case "first_name":
+		return o.FirstName
+	case "last_name":
+		return o.LastName
+	case "email":
+		return o.Email
+	case "avatar":
+		return o.Avatar
+	default:
+		return nil
+	}
+}
+
+func (o *User) pointersForFields(fields []string) ([]interface{}, error) {
+	pointers := make([]interface{}, len(fields))
+	for i, field := range fields {
+		ptr := o.fieldPointerForColumn(field)
 		if ptr == nil {
 			return nil, fmt.Errorf("unknown column %q", field)
 		}
@@ -302,15 +1152,54 @@ func (o *User) pointersForFields(fields []string) ([]interface{}, error) {
 func (o *User) assignField(name string, value interface{}) error {
 	switch name {
 	case "id":
-		o.ID = value.(int64)
+		switch v := value.(type) {
+		case int64:
+			o.ID = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
 
 		return nil
 	case "first_name":
-		o.FirstName = value.(string)
+		switch v := value.(type) {
+		case string:
+			o.FirstName = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
 
 		return nil
 	case "last_name":
-		o.LastName = value.(string)
+		switch v := value.(type) {
+		case string:
+			o.LastName = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "email":
+		switch v := value.(type) {
+		case nil:
+			o.Email = sql.NullString{}
+		case string:
+			o.Email = sql.NullString{String: v, Valid: true}
+		case sql.NullString:
+			o.Email = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "avatar":
+		switch v := value.(type) {
+		case nil:
+			o.Avatar = nil
+		case []byte:
+			o.Avatar = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
 
 		return nil
 	default:
@@ -318,21 +1207,50 @@ func (o *User) assignField(name string, value interface{}) error {
 	}
 }
 
+// UserPage is a page of Users, see Paginate
+type UserPage struct {
+	Records []*User
+
+	// Next is the cursor for the following page, empty if this is the last one
+	Next Cursor
+
+	// Previous is the cursor for the preceding page, empty if this is the first one
+	Previous Cursor
+}
+
+// UserRelation builds a query for Users. Relations are
+// immutable: each method returns a new relation, so a relation can be reused
+// as the base for other queries, and shared between goroutines.
 type UserRelation interface {
 	Relation
 
 	// All ...
 	All(ctx context.Context, db DB) ([]*User, error)
 
+	// Each calls fn for each of the records as they're read from the
+	// database, without loading them all at once. The rows stay open while fn
+	// runs, so some drivers don't let fn query the same connection; use
+	// FindInBatches for that. Associations can't be preloaded.
+	Each(ctx context.Context, db DB, fn func(*User) error) error
+
 	// Find ...
 	Find(ctx context.Context, db DB, id int64) (*User, error)
 
 	// FindBy ...
 	FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*User, error)
 
+	// FindInBatches calls fn with batches of up to batchSize records, ordered
+	// by primary key. Each batch is selected after the last key of the
+	// previous one, so the relation can't have an order, limit or offset.
+	FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*User) error) error
+
 	// First ...
 	First(ctx context.Context, db DB) (*User, error)
 
+	// Joins adds an INNER JOIN for each of the associations, given by the
+	// associated table's name. The records are selected once for every match.
+	Joins(associations ...string) UserRelation
+
 	// Last ...
 	Last(ctx context.Context, db DB) (*User, error)
 
@@ -342,12 +1260,32 @@ type UserRelation interface {
 	// New creates a User populated with the scope of the relation
 	New() *User
 
+	// Not adds the negation of a condition, given like in Where
+	Not(value interface{}, args ...interface{}) UserRelation
+
 	// Offset ...
 	Offset(offset int64) UserRelation
 
+	// Or selects the records matching the conditions of either relation. It
+	// panics when the relations don't have the same joins.
+	Or(other UserRelation) UserRelation
+
 	// Order ...
 	Order(query string, args ...string) UserRelation
 
+	// Paginate selects the page of up to pageSize records at the cursor. The
+	// pages follow the order of the relation, which needs to be by columns, and
+	// then the primary key. Pages are found by the values at their edges
+	// instead of by offset, so they stay fast and don't skip or repeat records
+	// when others are added or removed. Nullable columns can't be ordered by,
+	// as NULLs don't compare.
+	Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*UserPage, error)
+
+	// Preload loads the named associations of the selected records with one
+	// query per association. Nested associations are separated by dots, like
+	// "Posts.PostTags".
+	Preload(associations ...string) UserRelation
+
 	// Select ...
 	Select(fields ...string) UserRelation
 
@@ -357,8 +1295,23 @@ type UserRelation interface {
 	// Where ...
 	Where(value interface{}, args ...interface{}) UserRelation
 
+	// WhereBetween adds a condition that the field is between low and high, inclusive
+	WhereBetween(field string, low, high interface{}) UserRelation
+
 	// WhereEq ...
 	WhereEq(field string, value interface{}) UserRelation
+
+	// WhereIn adds a condition that the field has one of the values
+	WhereIn(field string, values ...interface{}) UserRelation
+
+	// WhereLike adds a condition that the field matches the LIKE pattern, see rel.Like
+	WhereLike(field, pattern string) UserRelation
+
+	// WhereNot adds a condition that the field doesn't equal the value, or isn't NULL for nil
+	WhereNot(field string, value interface{}) UserRelation
+
+	whereExpr() rel.Expr
+	joinExprs() []rel.Expr
 }
 
 // UsersQuerying gives you access to Users
@@ -369,14 +1322,38 @@ func Users() UsersQuerying {
 	return UsersQuerying{}
 }
 
+func (_ UsersQuerying) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return (&userRelation{}).Average(ctx, db, column)
+}
+
 func (_ UsersQuerying) Count(ctx context.Context, db DB) (int64, error) {
 	return (&userRelation{}).Count(ctx, db)
 }
 
+func (_ UsersQuerying) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return (&userRelation{}).CountBy(ctx, db, column)
+}
+
 func (_ UsersQuerying) DeleteAll(ctx context.Context, db DB) (int64, error) {
 	return (&userRelation{}).DeleteAll(ctx, db)
 }
 
+func (_ UsersQuerying) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&userRelation{}).Maximum(ctx, db, column, dest)
+}
+
+func (_ UsersQuerying) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&userRelation{}).Minimum(ctx, db, column, dest)
+}
+
+func (_ UsersQuerying) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return (&userRelation{}).Sum(ctx, db, column)
+}
+
+func (_ UsersQuerying) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return (&userRelation{}).SumInt(ctx, db, column)
+}
+
 func (_ UsersQuerying) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
 	return (&userRelation{}).UpdateAll(ctx, db, query, args...)
 }
@@ -385,6 +1362,10 @@ func (_ UsersQuerying) All(ctx context.Context, db DB) ([]*User, error) {
 	return (&userRelation{}).All(ctx, db)
 }
 
+func (_ UsersQuerying) Each(ctx context.Context, db DB, fn func(*User) error) error {
+	return (&userRelation{}).Each(ctx, db, fn)
+}
+
 func (_ UsersQuerying) Find(ctx context.Context, db DB, id int64) (*User, error) {
 	return (&userRelation{}).Find(ctx, db, id)
 }
@@ -393,10 +1374,18 @@ func (_ UsersQuerying) FindBy(ctx context.Context, db DB, query string, args ...
 	return (&userRelation{}).FindBy(ctx, db, query, args...)
 }
 
+func (_ UsersQuerying) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*User) error) error {
+	return (&userRelation{}).FindInBatches(ctx, db, batchSize, fn)
+}
+
 func (_ UsersQuerying) First(ctx context.Context, db DB) (*User, error) {
 	return (&userRelation{}).First(ctx, db)
 }
 
+func (_ UsersQuerying) Joins(associations ...string) UserRelation {
+	return (&userRelation{}).Joins(associations...)
+}
+
 func (_ UsersQuerying) Last(ctx context.Context, db DB) (*User, error) {
 	return (&userRelation{}).Last(ctx, db)
 }
@@ -409,14 +1398,30 @@ func (_ UsersQuerying) New() *User {
 	return (&userRelation{}).New()
 }
 
+func (_ UsersQuerying) Not(value interface{}, args ...interface{}) UserRelation {
+	return (&userRelation{}).Not(value, args...)
+}
+
 func (_ UsersQuerying) Offset(offset int64) UserRelation {
 	return (&userRelation{}).Offset(offset)
 }
 
+func (_ UsersQuerying) Or(other UserRelation) UserRelation {
+	return (&userRelation{}).Or(other)
+}
+
 func (_ UsersQuerying) Order(query string, args ...string) UserRelation {
 	return (&userRelation{}).Order(query, args...)
 }
 
+func (_ UsersQuerying) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*UserPage, error) {
+	return (&userRelation{}).Paginate(ctx, db, cursor, pageSize)
+}
+
+func (_ UsersQuerying) Preload(associations ...string) UserRelation {
+	return (&userRelation{}).Preload(associations...)
+}
+
 func (_ UsersQuerying) Select(fields ...string) UserRelation {
 	return (&userRelation{}).Select(fields...)
 }
@@ -429,109 +1434,515 @@ func (_ UsersQuerying) Where(value interface{}, args ...interface{}) UserRelatio
 	return (&userRelation{}).Where(value, args...)
 }
 
+func (_ UsersQuerying) WhereBetween(field string, low, high interface{}) UserRelation {
+	return (&userRelation{}).WhereBetween(field, low, high)
+}
+
 func (_ UsersQuerying) WhereEq(field string, value interface{}) UserRelation {
 	return (&userRelation{}).WhereEq(field, value)
 }
 
+func (_ UsersQuerying) WhereIn(field string, values ...interface{}) UserRelation {
+	return (&userRelation{}).WhereIn(field, values...)
+}
+
+func (_ UsersQuerying) WhereLike(field, pattern string) UserRelation {
+	return (&userRelation{}).WhereLike(field, pattern)
+}
+
+func (_ UsersQuerying) WhereNot(field string, value interface{}) UserRelation {
+	return (&userRelation{}).WhereNot(field, value)
+}
+
+func (_ UsersQuerying) whereExpr() rel.Expr {
+	return (&userRelation{}).whereExpr()
+}
+
+func (_ UsersQuerying) joinExprs() []rel.Expr {
+	return (&userRelation{}).joinExprs()
+}
+
 // FindBySQL returns all the Users selected by the given query
-func (_ UsersQuerying) FindBySQL(ctx context.Context, db DB, query string, args ...interface{}) ([]*User, error) {
+func (q UsersQuerying) FindBySQL(ctx context.Context, db DB, query string, args ...interface{}) ([]*User, error) {
 	var users []*User
-	rows, err := db.QueryContext(ctx, query, args...)
+	err := q.EachBySQL(ctx, db, query, args, func(o *User) error {
+		users = append(users, o)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return users, nil
+}
+
+// EachBySQL calls fn for each of the Users selected by the given
+// query, as they're read from the database
+func (_ UsersQuerying) EachBySQL(ctx context.Context, db DB, query string, args []interface{}, fn func(*User) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
 	defer rows.Close()
 
 	row := &User{}
 	row.persisted = true
 	fields, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	ptrs, err := row.pointersForFields(fields)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	for rows.Next() {
 		if err := rows.Scan(ptrs...); err != nil {
-			return nil, err
+			return err
 		}
 
 		o := &User{}
 		*o = *row
+		o.saved()
 
-		o.old.ID = o.ID
-		o.old.FirstName = o.FirstName
-		o.old.LastName = o.LastName
-
-		users = append(users, o)
+		if err := fn(o); err != nil {
+			return err
+		}
 	}
 
-	return users, rows.Err()
+	return rows.Err()
 }
 
-// CountBySQL executes the given query, giving a count
-func (_ UsersQuerying) CountBySQL(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
-	var count int64
-	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
-	return count, err
-}
+// InsertAll inserts the records with as few statements as the limit on bind
+// parameters allows. Unlike Save, it doesn't validate the records or call
+// hooks. The IDs the database assigns are filled in, which not every
+// dialect can do. When a statement fails, the rows of the ones before it
+// stay inserted; use a Transaction to insert all or nothing.
+func (_ UsersQuerying) InsertAll(ctx context.Context, db DB, records []*User) error {
+	// The IDs can only be worked out for statements that leave them all to the database
+	var withID, withoutID []*User
+	for _, o := range records {
+		if o.ID != 0 {
+			withID = append(withID, o)
+		} else {
+			withoutID = append(withoutID, o)
+		}
+	}
+	if _, ok := Dialect.FirstInsertID(0, 0); len(withoutID) > 0 && !ok && !Dialect.Returning() {
+		return errors.New("can't get the IDs of inserted rows with this dialect")
+	}
 
-type userRelation struct {
-	fields      []string
-	whereClause []rel.Expr
-	orderValues []rel.Expr
-	limit       int64
-	offset      int64
+	if err := insertUsers(ctx, db, withID, true, nil); err != nil {
+		return err
+	}
+	return insertUsers(ctx, db, withoutID, false, nil)
 }
 
-func (q *userRelation) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
-	clauses := []rel.Expr{rel.Literal{Text: query, Params: args}}
-
-	stmt := &rel.UpdateStatement{
-		Table:  "users",
-		Wheres: q.whereClause,
-		Values: clauses,
+// UpsertAll inserts the records, or updates the rows that have the same
+// values for the conflict columns, which need a unique index. Those are the
+// primary key if none are given. Like InsertAll, it doesn't validate the
+// records or call hooks. The records are read back from the rows afterwards,
+// as existing rows keep their keys and creation timestamps.
+func (_ UsersQuerying) UpsertAll(ctx context.Context, db DB, records []*User, conflictColumns ...string) error {
+	primaryKey := []string{"id"}
+	if len(conflictColumns) == 0 {
+		conflictColumns = primaryKey
 	}
-
-	query, values := stmt.Build()
-	res, err := db.ExecContext(ctx, query, values...)
-	if err != nil {
-		return 0, err
+	for _, column := range conflictColumns {
+		if (&User{}).fieldPointerForColumn(column) == nil {
+			return errors.Errorf("unknown column %q", column)
+		}
 	}
 
-	return res.RowsAffected()
-}
+	onConflict := &rel.OnConflict{Columns: conflictColumns}
+	for _, column := range []string{"first_name", "last_name", "email", "avatar"} {
+		if !containsColumn(conflictColumns, column) {
+			onConflict.Update = append(onConflict.Update, rel.Assignment{
+				Field: rel.Field{column},
+				Value: rel.Excluded{column},
+			})
+		}
+	}
 
-func (q *userRelation) ToSQL() (query string, args []interface{}) {
-	fields := q.columnFields()
-	columns := make([]rel.Expr, 0, len(fields))
-	for _, field := range fields {
-		columns = append(columns, &rel.Literal{Text: field})
+	var withID, withoutID []*User
+	for _, o := range records {
+		if o.ID != 0 {
+			withID = append(withID, o)
+		} else {
+			withoutID = append(withoutID, o)
+		}
 	}
-	s := rel.SelectStatement{
-		Columns: columns,
-		Table:   "users",
-		Wheres:  q.whereClause,
-		Orders:  q.orderValues,
-		Limit:   q.limit,
-		Offset:  q.offset,
+	if len(withoutID) > 0 && sameColumns(conflictColumns, primaryKey) {
+		return errors.New("can't upsert on the ID of records without one")
+	}
+	if err := insertUsers(ctx, db, withID, true, onConflict); err != nil {
+		return err
 	}
-	return s.Build()
+	return insertUsers(ctx, db, withoutID, false, onConflict)
 }
 
-func (q *userRelation) Count(ctx context.Context, db DB) (int64, error) {
-	q.fields = []string{"COUNT(*)"}
-
-	query, args := q.ToSQL()
-	return Users().CountBySQL(ctx, db, query, args...)
+// readUsersRows copies the rows with the same values for the
+// columns into the records, as upserted rows can keep values of the existing
+// ones. The rows an upsert returned are used first, the others are selected.
+func readUsersRows(ctx context.Context, db DB, records, returned []*User, columns []string) error {
+	keyOf := func(o *User) string {
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			values[i] = o.valueForColumn(column)
+		}
+		key, err := json.Marshal(values)
+		if err != nil {
+			panic(err)
+		}
+		return string(key)
+	}
+
+	byKey := make(map[string]*User, len(records))
+	for _, row := range returned {
+		byKey[keyOf(row)] = row
+	}
+	var missing []*User
+	for _, o := range records {
+		if _, ok := byKey[keyOf(o)]; !ok {
+			missing = append(missing, o)
+		}
+	}
+
+	size := Dialect.MaxParams() / len(columns)
+	for len(missing) > 0 {
+		chunk := missing
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		missing = missing[len(chunk):]
+
+		matches := make(rel.Or, len(chunk))
+		for i, o := range chunk {
+			match := make(rel.And, len(columns))
+			for j, column := range columns {
+				match[j] = rel.Equality{
+					Field: rel.Field{"users." + column},
+					Value: rel.BindParam{Value: o.valueForColumn(column)},
+				}
+			}
+			matches[i] = match
+		}
+		rows, err := (&userRelation{whereClause: []rel.Expr{matches}}).All(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			byKey[keyOf(row)] = row
+		}
+	}
+
+	for _, o := range records {
+		row, ok := byKey[keyOf(o)]
+		if !ok {
+			return errors.New("upserted row not found")
+		}
+		o.ID = row.ID
+		o.FirstName = row.FirstName
+		o.LastName = row.LastName
+		o.Email = row.Email
+		o.Avatar = row.Avatar
+		o.persisted = true
+		o.saved()
+	}
+	return nil
+}
+
+// insertUsers inserts the records in statements of as many rows as fit,
+// handling conflicts with onConflict if it's set. The IDs are inserted too if
+// withID is true, and filled in otherwise. Upserted records are read back
+// from their rows.
+func insertUsers(ctx context.Context, db DB, records []*User, withID bool, onConflict *rel.OnConflict) error {
+	var columns []string
+	if withID {
+		columns = append(columns, "id")
+	}
+	columns = append(columns, "first_name")
+	columns = append(columns, "last_name")
+	columns = append(columns, "email")
+	columns = append(columns, "avatar")
+	size := Dialect.MaxParams() / len(columns)
+
+	for len(records) > 0 {
+		chunk := records
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		records = records[len(chunk):]
+
+		stmt := &rel.InsertStatement{
+			Dialect:    Dialect,
+			Table:      "users",
+			Columns:    columns,
+			OnConflict: onConflict,
+		}
+		for _, o := range chunk {
+			row := make([]rel.Expr, 0, len(columns))
+			if withID {
+				row = append(row, &rel.BindParam{Value: o.ID})
+			}
+			row = append(row, &rel.BindParam{Value: o.FirstName})
+			row = append(row, &rel.BindParam{Value: o.LastName})
+			row = append(row, &rel.BindParam{Value: o.Email})
+			row = append(row, &rel.BindParam{Value: o.Avatar})
+			stmt.Rows = append(stmt.Rows, row)
+		}
+
+		// Existing rows keep some of their values, so upserted rows are read back
+		if onConflict != nil {
+			var returned []*User
+			if Dialect.Returning() {
+				stmt.Returning = userColumns
+				query, values := stmt.Build()
+				var err error
+				if returned, err = Users().FindBySQL(ctx, db, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				query, values := stmt.Build()
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			}
+			if err := readUsersRows(ctx, db, chunk, returned, onConflict.Columns); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Without the first ID, the database returns the IDs of the rows in the
+		// order they're inserted
+		_, counted := Dialect.FirstInsertID(0, 0)
+		returningIDs := !withID && !counted
+		if returningIDs {
+			stmt.Returning = []string{"id"}
+		}
+
+		query, values := stmt.Build()
+		if returningIDs {
+			rows, err := db.QueryContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			if err := scanUsersIDs(rows, chunk); err != nil {
+				return err
+			}
+		} else {
+			res, err := db.ExecContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			if !withID {
+				last, err := res.LastInsertId()
+				if err != nil {
+					return err
+				}
+				first, _ := Dialect.FirstInsertID(last, len(chunk))
+				for i, o := range chunk {
+					o.ID = first + int64(i)
+				}
+			}
+		}
+
+		for _, o := range chunk {
+			o.persisted = true
+			o.saved()
+		}
+	}
+
+	return nil
+}
+
+// scanUsersIDs reads the IDs returned by an insert into the records
+func scanUsersIDs(rows *sql.Rows, records []*User) error {
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		if n < len(records) {
+			if err := rows.Scan(&records[n].ID); err != nil {
+				return err
+			}
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if n != len(records) {
+		return errors.Errorf("inserted %d rows, but got %d IDs", len(records), n)
+	}
+	return nil
+}
+
+// CountBySQL executes the given query, giving a count
+func (_ UsersQuerying) CountBySQL(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	var count int64
+	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+type userRelation struct {
+	fields      []string
+	joins       []rel.Expr
+	preloads    []string
+	whereClause []rel.Expr
+	orderValues []rel.Expr
+	limit       int64
+	offset      int64
+}
+
+func (q *userRelation) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	clauses := []rel.Expr{rel.Literal{Text: query, Params: args}}
+
+	stmt := &rel.UpdateStatement{
+		Dialect: Dialect,
+		Table:   "users",
+		Wheres:  q.keyedWheres(),
+		Values:  clauses,
+	}
+
+	query, values := stmt.Build()
+	res, err := db.ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (q *userRelation) ToSQL() (query string, args []interface{}) {
+	return q.selectStatement().Build()
+}
+
+func (q *userRelation) selectStatement() *rel.SelectStatement {
+	return &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: q.columns(),
+		Table:   "users",
+		Joins:   q.joins,
+		Wheres:  q.whereClause,
+		Orders:  q.orderValues,
+		Limit:   q.limit,
+		Offset:  q.offset,
+	}
+}
+
+// keyedWheres are the conditions for updating or deleting the relation's
+// records. Those statements can't join, so with joins the records are
+// matched by their primary key instead.
+func (q *userRelation) keyedWheres() []rel.Expr {
+	if len(q.joins) == 0 {
+		return q.whereClause
+	}
+
+	keys := rel.ExprList{
+		rel.Field{"users.id"},
+	}
+	// MySQL can't select from the table that's changed in a subquery, unless
+	// it's wrapped in a derived table
+	return []rel.Expr{rel.In{
+		Left: rel.Grouping{Expr: keys},
+		Right: rel.ExprList{&rel.SelectStatement{
+			Columns: []rel.Expr{
+				rel.Field{"keys.id"},
+			},
+			Table: "keys",
+			From: &rel.SelectStatement{
+				Columns: keys,
+				Table:   "users",
+				Joins:   q.joins,
+				Wheres:  q.whereClause,
+			},
+		}},
+	}}
+}
+
+func (q *userRelation) Count(ctx context.Context, db DB) (int64, error) {
+	var count int64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "COUNT"}, &count)
+	return count, err
+}
+
+func (q *userRelation) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{rel.Field{column}, rel.Aggregate{Func: "COUNT"}}
+	s.Groups = []rel.Expr{rel.Field{column}}
+	s.Orders = nil
+
+	query, args := s.Build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[interface{}]int64)
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		// Text can come back as bytes, which can't be a map key
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		counts[value] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (q *userRelation) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	var sum sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Float64, err
+}
+
+func (q *userRelation) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	var sum sql.NullInt64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Int64, err
+}
+
+func (q *userRelation) Average(ctx context.Context, db DB, column string) (float64, error) {
+	var avg sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "AVG", Expr: rel.Field{column}}, &avg)
+	return avg.Float64, err
+}
+
+func (q *userRelation) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MIN", Expr: rel.Field{column}}, dest)
+}
+
+func (q *userRelation) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MAX", Expr: rel.Field{column}}, dest)
+}
+
+// aggregate selects a single aggregate over the relation's records into dest
+func (q *userRelation) aggregate(ctx context.Context, db DB, aggregate rel.Aggregate, dest interface{}) error {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{aggregate}
+	// Ordering only makes sense for the records themselves
+	s.Orders = nil
+
+	query, args := s.Build()
+	// Aggregates lose the column's type, so SQLite returns times as text
+	switch dest.(type) {
+	case *time.Time, *sql.NullTime, **time.Time:
+		dest = timeScanner{dest: dest}
+	}
+	return db.QueryRowContext(ctx, query, args...).Scan(dest)
 }
 
 func (q *userRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
 	s := rel.DeleteStatement{
-		Table:  "users",
-		Wheres: q.whereClause,
+		Dialect: Dialect,
+		Table:   "users",
+		Wheres:  q.keyedWheres(),
 	}
 
 	query, args := s.Build()
@@ -544,37 +1955,3947 @@ func (q *userRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
 	return res.RowsAffected()
 }
 
-func (q *userRelation) Where(value interface{}, args ...interface{}) UserRelation {
-	clauses, err := rel.UnpackWhere(value, args...)
-	if err != nil {
-		panic(err)
-	}
+func (q *userRelation) Where(value interface{}, args ...interface{}) UserRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, clauses...)
+
+	return q
+}
+
+func (q *userRelation) WhereEq(field string, value interface{}) UserRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Equality{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *userRelation) WhereBetween(field string, low, high interface{}) UserRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Between{
+		Field: rel.Field{field},
+		Low:   rel.BindParam{low},
+		High:  rel.BindParam{high},
+	})
+
+	return q
+}
+
+func (q *userRelation) WhereIn(field string, values ...interface{}) UserRelation {
+	q = q.clone()
+	params := make(rel.ExprList, len(values))
+	for i, value := range values {
+		params[i] = rel.BindParam{value}
+	}
+	q.whereClause = append(q.whereClause, rel.In{
+		Left:  rel.Field{field},
+		Right: params,
+	})
+
+	return q
+}
+
+func (q *userRelation) WhereLike(field, pattern string) UserRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Like{
+		Field:   rel.Field{field},
+		Pattern: rel.BindParam{pattern},
+	})
+
+	return q
+}
+
+func (q *userRelation) WhereNot(field string, value interface{}) UserRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.NotEqual{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *userRelation) Not(value interface{}, args ...interface{}) UserRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, rel.Not{Expr: rel.And(clauses)})
+
+	return q
+}
+
+func (q *userRelation) Or(other UserRelation) UserRelation {
+	if !sameJoins(q.joins, other.joinExprs()) {
+		panic("relation passed to Or must have the same joins")
+	}
+	q = q.clone()
+	q.whereClause = []rel.Expr{rel.Or{q.whereExpr(), other.whereExpr()}}
+
+	return q
+}
+
+// clone copies the relation, so the methods building on it leave it as it
+// is. The slices are capped, so appending to them copies them as well.
+func (q *userRelation) clone() *userRelation {
+	c := *q
+	c.fields = c.fields[:len(c.fields):len(c.fields)]
+	c.joins = c.joins[:len(c.joins):len(c.joins)]
+	c.preloads = c.preloads[:len(c.preloads):len(c.preloads)]
+	c.whereClause = c.whereClause[:len(c.whereClause):len(c.whereClause)]
+	c.orderValues = c.orderValues[:len(c.orderValues):len(c.orderValues)]
+	return &c
+}
+
+// whereExpr combines the conditions of the relation into a single expression
+func (q *userRelation) whereExpr() rel.Expr {
+	return rel.And(q.whereClause)
+}
+
+func (q *userRelation) joinExprs() []rel.Expr {
+	return q.joins
+}
+
+func (q *userRelation) Joins(associations ...string) UserRelation {
+	q = q.clone()
+	for _, association := range associations {
+		switch association {
+		case "posts":
+			q.joins = append(q.joins, rel.InnerJoin{
+				Table: "posts",
+				On: rel.Equality{
+					Field: rel.Field{"posts.user_id"},
+					Value: rel.Field{"users.id"},
+				},
+			})
+		default:
+			panic(fmt.Sprintf("unknown association %q", association))
+		}
+	}
+
+	return q
+}
+
+func (q *userRelation) Limit(limit int64) UserRelation {
+	q = q.clone()
+	q.limit = limit
+	return q
+}
+
+func (q *userRelation) New() *User {
+	o := &User{}
+	for _, w := range q.whereClause {
+		if eq, ok := w.(rel.Equality); ok {
+			name := strings.TrimPrefix(eq.Field.Name, "users.")
+			if eq.Value == nil {
+				o.assignField(name, nil)
+			} else if bind, ok := eq.Value.(rel.BindParam); ok {
+				o.assignField(name, bind.Value)
+			}
+		}
+	}
+
+	return o
+}
+
+func (q *userRelation) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*UserPage, error) {
+	if pageSize <= 0 {
+		return nil, errors.Errorf("invalid page size %d", pageSize)
+	}
+	if q.limit != 0 || q.offset != 0 {
+		return nil, errors.New("Paginate can't be used with a limit or offset")
+	}
+
+	keys, err := parseOrder("users", q.orderValues)
+	if err != nil {
+		return nil, err
+	}
+	edge := &User{}
+	for _, key := range keys {
+		if !edge.sortable(key.column) {
+			return nil, errors.Errorf("can't paginate by %q", key.column)
+		}
+	}
+	keys = appendSortKey(keys, "id")
+
+	page := q.clone()
+	page.limit = int64(pageSize) + 1
+	// The cursors need the values of the keys
+	if page.fields != nil {
+		for _, key := range keys {
+			page.fields = append(page.fields, "users."+key.column)
+		}
+	}
+	var position cursorPosition
+	if cursor != "" {
+		if position, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			value, ok := position.Values[key.column]
+			if !ok || json.Unmarshal(value, edge.fieldPointerForColumn(key.column)) != nil {
+				return nil, errors.Errorf("invalid cursor %q", cursor)
+			}
+		}
+		page.whereClause = append(page.whereClause, seek("users", keys, edge.valueForColumn, position.Before))
+	}
+	page.orderValues = nil
+	for _, key := range keys {
+		field := rel.Field{Name: "users." + key.column}
+		if key.descending != position.Before {
+			page.orderValues = append(page.orderValues, rel.Descending{Expr: field})
+		} else {
+			page.orderValues = append(page.orderValues, rel.Ascending{Expr: field})
+		}
+	}
+
+	records, err := page.All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	more := len(records) > pageSize
+	if more {
+		records = records[:pageSize]
+	}
+	if position.Before {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	result := &UserPage{Records: records}
+	if len(records) == 0 {
+		return result, nil
+	}
+	if more && !position.Before || cursor != "" && position.Before {
+		result.Next = records[len(records)-1].cursor(keys, false)
+	}
+	if more && position.Before || cursor != "" && !position.Before {
+		result.Previous = records[0].cursor(keys, true)
+	}
+	return result, nil
+}
+
+func (q *userRelation) Preload(associations ...string) UserRelation {
+	q = q.clone()
+	q.preloads = append(q.preloads, associations...)
+	return q
+}
+
+func (q *userRelation) Select(fields ...string) UserRelation {
+	q = q.clone()
+	q.fields = append(q.fields, fields...)
+	return q
+}
+
+func (q *userRelation) Offset(offset int64) UserRelation {
+	q = q.clone()
+	q.offset = offset
+	return q
+}
+
+func (q *userRelation) columns() []rel.Expr {
+	if q.fields == nil {
+		return []rel.Expr{
+			rel.Field{"users.id"},
+			rel.Field{"users.first_name"},
+			rel.Field{"users.last_name"},
+			rel.Field{"users.email"},
+			rel.Field{"users.avatar"},
+		}
+	}
+
+	columns := make([]rel.Expr, 0, len(q.fields))
+	for _, field := range q.fields {
+		columns = append(columns, &rel.Literal{Text: field})
+	}
+	return columns
+}
+
+func (q *userRelation) All(ctx context.Context, db DB) ([]*User, error) {
+	query, args := q.ToSQL()
+	records, err := Users().FindBySQL(ctx, db, query, args...)
+	if err != nil || len(q.preloads) == 0 {
+		return records, err
+	}
+
+	if err := preloadUsers(ctx, db, records, q.preloads); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (q *userRelation) Take(ctx context.Context, db DB) (*User, error) {
+	q = q.clone()
+	q.limit = 1
+	os, err := q.All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(os) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return os[0], nil
+}
+
+func (q *userRelation) Each(ctx context.Context, db DB, fn func(*User) error) error {
+	if len(q.preloads) > 0 {
+		return errors.New("Each can't preload associations, use FindInBatches")
+	}
+	query, args := q.ToSQL()
+	return Users().EachBySQL(ctx, db, query, args, fn)
+}
+
+func (q *userRelation) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*User) error) error {
+	if batchSize <= 0 {
+		return errors.Errorf("invalid batch size %d", batchSize)
+	}
+	if len(q.orderValues) > 0 || q.limit != 0 || q.offset != 0 {
+		return errors.New("FindInBatches can't be used with an order, limit or offset")
+	}
+
+	batch := q.clone()
+	batch.limit = int64(batchSize)
+	// The next batch starts after the key of the last record, so it has to be
+	// selected
+	if batch.fields != nil {
+		batch.fields = append(batch.fields, "users.id")
+	}
+	batch.orderValues = append(batch.orderValues, rel.Ascending{Expr: rel.Field{"users.id"}})
+	for {
+		records, err := batch.All(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+
+		batch = batch.clone()
+		batch.whereClause = append(q.whereClause[:len(q.whereClause):len(q.whereClause)], records[len(records)-1].afterKey())
+	}
+}
+
+func (q *userRelation) Find(ctx context.Context, db DB, id int64) (*User, error) {
+	return q.WhereEq("users.id", id).Take(ctx, db)
+}
+
+func (q *userRelation) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*User, error) {
+	return q.Where(query, args...).Take(ctx, db)
+}
+
+func (q *userRelation) First(ctx context.Context, db DB) (*User, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Ascending{Expr: rel.Field{"users.id"}})
+	return q.Take(ctx, db)
+}
+
+func (q *userRelation) Last(ctx context.Context, db DB) (*User, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Descending{Expr: rel.Field{"users.id"}})
+	return q.Take(ctx, db)
+}
+
+func (q *userRelation) Order(query string, args ...string) UserRelation {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, &rel.Literal{Text: query})
+
+	for i := 0; i < len(args); i++ {
+		q.orderValues = append(q.orderValues, &rel.Literal{Text: args[i]})
+	}
+
+	return q
+}
+
+// preloadUsers fills the association caches of the records for the
+// given association paths
+func preloadUsers(ctx context.Context, db DB, records []*User, paths []string) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// The nested paths are preloaded along with their first association
+	var names []string
+	nested := make(map[string][]string)
+	for _, path := range paths {
+		name, rest := path, ""
+		if i := strings.Index(path, "."); i >= 0 {
+			name, rest = path[:i], path[i+1:]
+		}
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = []string{}
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+
+	for _, name := range names {
+		switch name {
+		case "Posts":
+			var keys []interface{}
+			seen := make(map[int64]bool, len(records))
+			for _, o := range records {
+				if !seen[o.ID] {
+					seen[o.ID] = true
+					keys = append(keys, o.ID)
+				}
+			}
+
+			byKey := make(map[int64][]*Post)
+			for _, chunk := range keyChunks(keys) {
+				children, err := Posts().WhereIn("posts.user_id", chunk...).Preload(nested[name]...).All(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, c := range children {
+					byKey[c.UserID] = append(byKey[c.UserID], c)
+				}
+			}
+			for _, o := range records {
+				o.associations.Posts.records = byKey[o.ID]
+				o.associations.Posts.loaded = true
+			}
+		default:
+			return errors.Errorf("users: unknown association %q", name)
+		}
+	}
+
+	return nil
+}
+
+type Category struct {
+	// Slug ...
+	Slug string
+
+	// Name ...
+	Name string
+
+	// If true, then this record exists in the DB
+	persisted bool
+	deleted   bool
+
+	old struct {
+		// Slug ...
+		Slug string
+
+		// Name ...
+		Name string
+	}
+
+	associations struct {
+		Posts struct {
+			loaded  bool
+			records []*Post
+		}
+	}
+}
+
+func (o *Category) Posts() CategoryHasManyPostsCollection {
+	return (*categoryHasManyPostsCollection)(o)
+}
+
+type CategoryHasManyPostsCollection interface {
+	PostRelation
+
+	// Loaded specifies whether the association has been loaded
+	Loaded() bool
+
+	// Reset clears out the association
+	Reset()
+}
+
+type categoryHasManyPostsCollection Category
+
+func (o *categoryHasManyPostsCollection) relation() PostRelation {
+	return Posts().WhereEq("posts.category_id", o.Slug)
+}
+
+func (o *categoryHasManyPostsCollection) Loaded() bool {
+	return o.associations.Posts.loaded
+}
+
+func (o *categoryHasManyPostsCollection) Reset() {
+	o.associations.Posts.records = nil
+	o.associations.Posts.loaded = false
+}
+
+func (o *categoryHasManyPostsCollection) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return o.relation().Average(ctx, db, column)
+}
+
+func (o *categoryHasManyPostsCollection) Count(ctx context.Context, db DB) (int64, error) {
+	return o.relation().Count(ctx, db)
+}
+
+func (o *categoryHasManyPostsCollection) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return o.relation().CountBy(ctx, db, column)
+}
+
+func (o *categoryHasManyPostsCollection) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	return o.relation().DeleteAll(ctx, db)
+}
+
+func (o *categoryHasManyPostsCollection) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return o.relation().Maximum(ctx, db, column, dest)
+}
+
+func (o *categoryHasManyPostsCollection) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return o.relation().Minimum(ctx, db, column, dest)
+}
+
+func (o *categoryHasManyPostsCollection) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return o.relation().Sum(ctx, db, column)
+}
+
+func (o *categoryHasManyPostsCollection) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return o.relation().SumInt(ctx, db, column)
+}
+
+func (o *categoryHasManyPostsCollection) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	return o.relation().UpdateAll(ctx, db, query, args...)
+}
+
+func (o *categoryHasManyPostsCollection) All(ctx context.Context, db DB) ([]*Post, error) {
+	if o.Loaded() {
+		return o.associations.Posts.records, nil
+	}
+
+	records, err := o.relation().All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	o.associations.Posts.records = records
+	o.associations.Posts.loaded = true
+
+	return records, nil
+}
+
+func (o *categoryHasManyPostsCollection) Each(ctx context.Context, db DB, fn func(*Post) error) error {
+	return o.relation().Each(ctx, db, fn)
+}
+
+func (o *categoryHasManyPostsCollection) Find(ctx context.Context, db DB, id int64) (*Post, error) {
+	return o.relation().Find(ctx, db, id)
+}
+
+func (o *categoryHasManyPostsCollection) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error) {
+	return o.relation().FindBy(ctx, db, query, args...)
+}
+
+func (o *categoryHasManyPostsCollection) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Post) error) error {
+	return o.relation().FindInBatches(ctx, db, batchSize, fn)
+}
+
+func (o *categoryHasManyPostsCollection) First(ctx context.Context, db DB) (*Post, error) {
+	return o.relation().First(ctx, db)
+}
+
+func (o *categoryHasManyPostsCollection) Joins(associations ...string) PostRelation {
+	return o.relation().Joins(associations...)
+}
+
+func (o *categoryHasManyPostsCollection) Last(ctx context.Context, db DB) (*Post, error) {
+	return o.relation().Last(ctx, db)
+}
+
+func (o *categoryHasManyPostsCollection) Limit(limit int64) PostRelation {
+	return o.relation().Limit(limit)
+}
+
+func (o *categoryHasManyPostsCollection) New() *Post {
+	return o.relation().New()
+}
+
+func (o *categoryHasManyPostsCollection) Not(value interface{}, args ...interface{}) PostRelation {
+	return o.relation().Not(value, args...)
+}
+
+func (o *categoryHasManyPostsCollection) Offset(offset int64) PostRelation {
+	return o.relation().Offset(offset)
+}
+
+func (o *categoryHasManyPostsCollection) Or(other PostRelation) PostRelation {
+	return o.relation().Or(other)
+}
+
+func (o *categoryHasManyPostsCollection) Order(query string, args ...string) PostRelation {
+	return o.relation().Order(query, args...)
+}
+
+func (o *categoryHasManyPostsCollection) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostPage, error) {
+	return o.relation().Paginate(ctx, db, cursor, pageSize)
+}
+
+func (o *categoryHasManyPostsCollection) Preload(associations ...string) PostRelation {
+	return o.relation().Preload(associations...)
+}
+
+func (o *categoryHasManyPostsCollection) Select(fields ...string) PostRelation {
+	return o.relation().Select(fields...)
+}
+
+func (o *categoryHasManyPostsCollection) Take(ctx context.Context, db DB) (*Post, error) {
+	return o.relation().Take(ctx, db)
+}
+
+func (o *categoryHasManyPostsCollection) Where(value interface{}, args ...interface{}) PostRelation {
+	return o.relation().Where(value, args...)
+}
+
+func (o *categoryHasManyPostsCollection) WhereBetween(field string, low, high interface{}) PostRelation {
+	return o.relation().WhereBetween(field, low, high)
+}
+
+func (o *categoryHasManyPostsCollection) WhereEq(field string, value interface{}) PostRelation {
+	return o.relation().WhereEq(field, value)
+}
+
+func (o *categoryHasManyPostsCollection) WhereIn(field string, values ...interface{}) PostRelation {
+	return o.relation().WhereIn(field, values...)
+}
+
+func (o *categoryHasManyPostsCollection) WhereLike(field, pattern string) PostRelation {
+	return o.relation().WhereLike(field, pattern)
+}
+
+func (o *categoryHasManyPostsCollection) WhereNot(field string, value interface{}) PostRelation {
+	return o.relation().WhereNot(field, value)
+}
+
+func (o *categoryHasManyPostsCollection) whereExpr() rel.Expr {
+	return o.relation().whereExpr()
+}
+
+func (o *categoryHasManyPostsCollection) joinExprs() []rel.Expr {
+	return o.relation().joinExprs()
+}
+
+func (o *Category) Save(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+
+	if err := o.Validate(ctx, db); err != nil {
+		return err
+	}
+
+	if hook, ok := interface{}(o).(BeforeSaveHook); ok {
+		if err := hook.BeforeSave(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	if o.persisted {
+		if hook, ok := interface{}(o).(BeforeUpdateHook); ok {
+			if err := hook.BeforeUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		stmt := &rel.UpdateStatement{
+			Dialect: Dialect,
+			Table:   "categories",
+			Wheres:  o.keyWheres(),
+		}
+
+		if o.Slug != o.old.Slug {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"slug"},
+				Value: &rel.BindParam{
+					Value: o.Slug,
+				},
+			})
+		}
+
+		if o.Name != o.old.Name {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"name"},
+				Value: &rel.BindParam{
+					Value: o.Name,
+				},
+			})
+		}
+
+		// There's nothing to write when no field changed
+		if len(stmt.Values) > 0 {
+			if Dialect.Returning() {
+				stmt.Returning = categoryColumns
+			}
+			query, values := stmt.Build()
+			if Dialect.Returning() {
+				if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+				o.saved()
+				if err := o.reload(ctx, db); err != nil {
+					return err
+				}
+			}
+		}
+		if hook, ok := interface{}(o).(AfterUpdateHook); ok {
+			if err := hook.AfterUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+	} else {
+		if hook, ok := interface{}(o).(BeforeCreateHook); ok {
+			if err := hook.BeforeCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		stmt := &rel.InsertStatement{
+			Dialect: Dialect,
+			Table:   "categories",
+		}
+
+		stmt.Columns = append(stmt.Columns, "slug")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.Slug,
+		})
+		stmt.Columns = append(stmt.Columns, "name")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.Name,
+		})
+
+		if Dialect.Returning() {
+			stmt.Returning = categoryColumns
+		}
+		query, values := stmt.Build()
+		if Dialect.Returning() {
+			if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+		} else {
+			_, err := db.ExecContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+
+			o.saved()
+			if err := o.reload(ctx, db); err != nil {
+				return err
+			}
+		}
+		if hook, ok := interface{}(o).(AfterCreateHook); ok {
+			if err := hook.AfterCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hook, ok := interface{}(o).(AfterSaveHook); ok {
+		if err := hook.AfterSave(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upsert inserts the record, or updates the row that has the same values for
+// the conflict columns, see UpsertAll
+func (o *Category) Upsert(ctx context.Context, db DB, conflictColumns ...string) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	return Categories().UpsertAll(ctx, db, []*Category{o}, conflictColumns...)
+}
+
+var categorySlugFormat = regexp.MustCompile("^[a-z0-9-]*$")
+
+// Validate checks the values against the validations in the schema. It
+// returns ValidationErrors when they don't pass.
+func (o *Category) Validate(ctx context.Context, db DB) error {
+	errs := ValidationErrors{}
+
+	{
+		v := o.Slug
+		if strings.TrimSpace(v) == "" {
+			errs.Add("slug", "can't be blank")
+		}
+		length := utf8.RuneCountInString(v)
+		if length > 32 {
+			errs.Add("slug", "is too long (maximum is 32 characters)")
+		}
+		if !categorySlugFormat.MatchString(v) {
+			errs.Add("slug", "is invalid")
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// IsPersisted is true once the record has been loaded or saved
+func (o *Category) IsPersisted() bool {
+	return o.persisted
+}
+
+// IsDeleted is true once the record has been deleted
+func (o *Category) IsDeleted() bool {
+	return o.deleted
+}
+
+// IsChanged is true if any of the fields differ from the row the record was
+// loaded or last saved as. For new records, that's any field that isn't zero.
+func (o *Category) IsChanged() bool {
+	return o.Slug != o.old.Slug ||
+		o.Name != o.old.Name
+}
+
+// ChangedColumns returns the columns of the fields that changed, in the order
+// of the table
+func (o *Category) ChangedColumns() []string {
+	var columns []string
+	if o.Slug != o.old.Slug {
+		columns = append(columns, "slug")
+	}
+	if o.Name != o.old.Name {
+		columns = append(columns, "name")
+	}
+	return columns
+}
+
+// Changes returns the old and new value of the changed fields by column
+func (o *Category) Changes() map[string][2]interface{} {
+	changes := map[string][2]interface{}{}
+	if o.Slug != o.old.Slug {
+		changes["slug"] = [2]interface{}{o.old.Slug, o.Slug}
+	}
+	if o.Name != o.old.Name {
+		changes["name"] = [2]interface{}{o.old.Name, o.Name}
+	}
+	return changes
+}
+
+// Restore reverts the fields to the row the record was loaded or last saved
+// as, undoing the changes
+func (o *Category) Restore() {
+	o.Slug = o.old.Slug
+	o.Name = o.old.Name
+}
+
+// Reload reads the record's row again and resets the association caches.
+// It returns ErrNotFound if the row no longer exists.
+func (o *Category) Reload(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	if !o.persisted {
+		return fmt.Errorf("record not persisted")
+	}
+	if err := o.reload(ctx, db); err != nil {
+		return err
+	}
+	var reset Category
+	o.associations = reset.associations
+	return nil
+}
+
+// saved records that the fields match the row
+func (o *Category) saved() {
+	o.old.Slug = o.Slug
+	o.old.Name = o.Name
+}
+
+// categoryColumns are the columns Save reads back after writing a row
+var categoryColumns = []string{"slug", "name"}
+
+// scan reads the row's columns into the record, which then matches it
+func (o *Category) scan(row *sql.Row) error {
+	ptrs, err := o.pointersForFields(categoryColumns)
+	if err != nil {
+		return err
+	}
+	if err := row.Scan(ptrs...); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	o.saved()
+	return nil
+}
+
+// reload selects the record's row again, to see the values the database set
+// when the dialect can't return them from the insert or update
+func (o *Category) reload(ctx context.Context, db DB) error {
+	stmt := &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: (&categoryRelation{}).columns(),
+		Table:   "categories",
+		Wheres:  o.keyWheres(),
+	}
+	query, values := stmt.Build()
+	return o.scan(db.QueryRowContext(ctx, query, values...))
+}
+
+func (o *Category) Delete(ctx context.Context, db DB) error {
+	if hook, ok := interface{}(o).(BeforeDeleteHook); ok {
+		if err := hook.BeforeDelete(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	_, err := (&categoryRelation{whereClause: o.keyWheres()}).DeleteAll(ctx, db)
+	if err != nil {
+		return err
+	}
+	o.deleted = true
+	if hook, ok := interface{}(o).(AfterDeleteHook); ok {
+		if err := hook.AfterDelete(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyWheres matches the record's row. Once persisted, that's by the key it
+// was loaded or last saved with, so changes to the key are saved too.
+func (o *Category) keyWheres() []rel.Expr {
+	if !o.persisted {
+		return []rel.Expr{
+			rel.Equality{
+				Field: rel.Field{"slug"},
+				Value: rel.BindParam{Value: o.Slug},
+			},
+		}
+	}
+
+	return []rel.Expr{
+		rel.Equality{
+			Field: rel.Field{"slug"},
+			Value: rel.BindParam{Value: o.old.Slug},
+		},
+	}
+}
+
+// afterKey matches the rows ordered after the record by primary key
+func (o *Category) afterKey() rel.Expr {
+	return rel.Or{
+		rel.And{
+			rel.GreaterThan{
+				Field: rel.Field{"categories.slug"},
+				Value: rel.BindParam{Value: o.Slug},
+			},
+		},
+	}
+}
+
+// cursor is the position of the record in the order of the keys
+func (o *Category) cursor(keys []sortKey, before bool) Cursor {
+	position := cursorPosition{Before: before, Values: make(map[string]json.RawMessage, len(keys))}
+	for _, key := range keys {
+		value, err := json.Marshal(o.valueForColumn(key.column))
+		if err != nil {
+			panic(err)
+		}
+		position.Values[key.column] = value
+	}
+	return position.encode()
+}
+
+func (o *Category) fieldPointerForColumn(column string) interface{} {
+	switch column {
+	case "slug":
+		return &o.Slug
+	case "name":
+		return &o.Name
+	default:
+		return nil
+	}
+}
+
+// sortable is true for the columns Paginate can order by, which are the ones
+// that can't be NULL
+func (o *Category) sortable(column string) bool {
+	switch column {
+	case "slug":
+		return true
+	case "name":
+		return true
+	default:
+		return false
+	}
+}
+
+func (o *Category) valueForColumn(column string) interface{} {
+	switch column {
+	case "slug":
+		return o.Slug
+	case "name":
+		return o.Name
+	default:
+		return nil
+	}
+}
+
+func (o *Category) pointersForFields(fields []string) ([]interface{}, error) {
+	pointers := make([]interface{}, len(fields))
+	for i, field := range fields {
+		ptr := o.fieldPointerForColumn(field)
+		if ptr == nil {
+			return nil, fmt.Errorf("unknown column %q", field)
+		}
+		pointers[i] = ptr
+	}
+	return pointers, nil
+}
+
+// assignField sets the field to the value.
+// It returns an error if the field doesn't exist or the value is the wrong type.
+func (o *Category) assignField(name string, value interface{}) error {
+	switch name {
+	case "slug":
+		switch v := value.(type) {
+		case string:
+			o.Slug = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "name":
+		switch v := value.(type) {
+		case string:
+			o.Name = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	default:
+		return errors.Errorf("unknown field: %s", name)
+	}
+}
+
+// CategoryPage is a page of Categories, see Paginate
+type CategoryPage struct {
+	Records []*Category
+
+	// Next is the cursor for the following page, empty if this is the last one
+	Next Cursor
+
+	// Previous is the cursor for the preceding page, empty if this is the first one
+	Previous Cursor
+}
+
+// CategoryRelation builds a query for Categories. Relations are
+// immutable: each method returns a new relation, so a relation can be reused
+// as the base for other queries, and shared between goroutines.
+type CategoryRelation interface {
+	Relation
+
+	// All ...
+	All(ctx context.Context, db DB) ([]*Category, error)
+
+	// Each calls fn for each of the records as they're read from the
+	// database, without loading them all at once. The rows stay open while fn
+	// runs, so some drivers don't let fn query the same connection; use
+	// FindInBatches for that. Associations can't be preloaded.
+	Each(ctx context.Context, db DB, fn func(*Category) error) error
+
+	// Find ...
+	Find(ctx context.Context, db DB, slug string) (*Category, error)
+
+	// FindBy ...
+	FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Category, error)
+
+	// FindInBatches calls fn with batches of up to batchSize records, ordered
+	// by primary key. Each batch is selected after the last key of the
+	// previous one, so the relation can't have an order, limit or offset.
+	FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Category) error) error
+
+	// First ...
+	First(ctx context.Context, db DB) (*Category, error)
+
+	// Joins adds an INNER JOIN for each of the associations, given by the
+	// associated table's name. The records are selected once for every match.
+	Joins(associations ...string) CategoryRelation
+
+	// Last ...
+	Last(ctx context.Context, db DB) (*Category, error)
+
+	// Limit ...
+	Limit(limit int64) CategoryRelation
+
+	// New creates a Category populated with the scope of the relation
+	New() *Category
+
+	// Not adds the negation of a condition, given like in Where
+	Not(value interface{}, args ...interface{}) CategoryRelation
+
+	// Offset ...
+	Offset(offset int64) CategoryRelation
+
+	// Or selects the records matching the conditions of either relation. It
+	// panics when the relations don't have the same joins.
+	Or(other CategoryRelation) CategoryRelation
+
+	// Order ...
+	Order(query string, args ...string) CategoryRelation
+
+	// Paginate selects the page of up to pageSize records at the cursor. The
+	// pages follow the order of the relation, which needs to be by columns, and
+	// then the primary key. Pages are found by the values at their edges
+	// instead of by offset, so they stay fast and don't skip or repeat records
+	// when others are added or removed. Nullable columns can't be ordered by,
+	// as NULLs don't compare.
+	Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*CategoryPage, error)
+
+	// Preload loads the named associations of the selected records with one
+	// query per association. Nested associations are separated by dots, like
+	// "Posts.PostTags".
+	Preload(associations ...string) CategoryRelation
+
+	// Select ...
+	Select(fields ...string) CategoryRelation
+
+	// Take ...
+	Take(ctx context.Context, db DB) (*Category, error)
+
+	// Where ...
+	Where(value interface{}, args ...interface{}) CategoryRelation
+
+	// WhereBetween adds a condition that the field is between low and high, inclusive
+	WhereBetween(field string, low, high interface{}) CategoryRelation
+
+	// WhereEq ...
+	WhereEq(field string, value interface{}) CategoryRelation
+
+	// WhereIn adds a condition that the field has one of the values
+	WhereIn(field string, values ...interface{}) CategoryRelation
+
+	// WhereLike adds a condition that the field matches the LIKE pattern, see rel.Like
+	WhereLike(field, pattern string) CategoryRelation
+
+	// WhereNot adds a condition that the field doesn't equal the value, or isn't NULL for nil
+	WhereNot(field string, value interface{}) CategoryRelation
+
+	whereExpr() rel.Expr
+	joinExprs() []rel.Expr
+}
+
+// CategoriesQuerying gives you access to Categories
+type CategoriesQuerying struct{}
+
+// CategoriesQuerying gives you access to Categories
+func Categories() CategoriesQuerying {
+	return CategoriesQuerying{}
+}
+
+func (_ CategoriesQuerying) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return (&categoryRelation{}).Average(ctx, db, column)
+}
+
+func (_ CategoriesQuerying) Count(ctx context.Context, db DB) (int64, error) {
+	return (&categoryRelation{}).Count(ctx, db)
+}
+
+func (_ CategoriesQuerying) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return (&categoryRelation{}).CountBy(ctx, db, column)
+}
+
+func (_ CategoriesQuerying) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	return (&categoryRelation{}).DeleteAll(ctx, db)
+}
+
+func (_ CategoriesQuerying) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&categoryRelation{}).Maximum(ctx, db, column, dest)
+}
+
+func (_ CategoriesQuerying) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&categoryRelation{}).Minimum(ctx, db, column, dest)
+}
+
+func (_ CategoriesQuerying) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return (&categoryRelation{}).Sum(ctx, db, column)
+}
+
+func (_ CategoriesQuerying) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return (&categoryRelation{}).SumInt(ctx, db, column)
+}
+
+func (_ CategoriesQuerying) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	return (&categoryRelation{}).UpdateAll(ctx, db, query, args...)
+}
+
+func (_ CategoriesQuerying) All(ctx context.Context, db DB) ([]*Category, error) {
+	return (&categoryRelation{}).All(ctx, db)
+}
+
+func (_ CategoriesQuerying) Each(ctx context.Context, db DB, fn func(*Category) error) error {
+	return (&categoryRelation{}).Each(ctx, db, fn)
+}
+
+func (_ CategoriesQuerying) Find(ctx context.Context, db DB, slug string) (*Category, error) {
+	return (&categoryRelation{}).Find(ctx, db, slug)
+}
+
+func (_ CategoriesQuerying) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Category, error) {
+	return (&categoryRelation{}).FindBy(ctx, db, query, args...)
+}
+
+func (_ CategoriesQuerying) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Category) error) error {
+	return (&categoryRelation{}).FindInBatches(ctx, db, batchSize, fn)
+}
+
+func (_ CategoriesQuerying) First(ctx context.Context, db DB) (*Category, error) {
+	return (&categoryRelation{}).First(ctx, db)
+}
+
+func (_ CategoriesQuerying) Joins(associations ...string) CategoryRelation {
+	return (&categoryRelation{}).Joins(associations...)
+}
+
+func (_ CategoriesQuerying) Last(ctx context.Context, db DB) (*Category, error) {
+	return (&categoryRelation{}).Last(ctx, db)
+}
+
+func (_ CategoriesQuerying) Limit(limit int64) CategoryRelation {
+	return (&categoryRelation{}).Limit(limit)
+}
+
+func (_ CategoriesQuerying) New() *Category {
+	return (&categoryRelation{}).New()
+}
+
+func (_ CategoriesQuerying) Not(value interface{}, args ...interface{}) CategoryRelation {
+	return (&categoryRelation{}).Not(value, args...)
+}
+
+func (_ CategoriesQuerying) Offset(offset int64) CategoryRelation {
+	return (&categoryRelation{}).Offset(offset)
+}
+
+func (_ CategoriesQuerying) Or(other CategoryRelation) CategoryRelation {
+	return (&categoryRelation{}).Or(other)
+}
+
+func (_ CategoriesQuerying) Order(query string, args ...string) CategoryRelation {
+	return (&categoryRelation{}).Order(query, args...)
+}
+
+func (_ CategoriesQuerying) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*CategoryPage, error) {
+	return (&categoryRelation{}).Paginate(ctx, db, cursor, pageSize)
+}
+
+func (_ CategoriesQuerying) Preload(associations ...string) CategoryRelation {
+	return (&categoryRelation{}).Preload(associations...)
+}
+
+func (_ CategoriesQuerying) Select(fields ...string) CategoryRelation {
+	return (&categoryRelation{}).Select(fields...)
+}
+
+func (_ CategoriesQuerying) Take(ctx context.Context, db DB) (*Category, error) {
+	return (&categoryRelation{}).Take(ctx, db)
+}
+
+func (_ CategoriesQuerying) Where(value interface{}, args ...interface{}) CategoryRelation {
+	return (&categoryRelation{}).Where(value, args...)
+}
+
+func (_ CategoriesQuerying) WhereBetween(field string, low, high interface{}) CategoryRelation {
+	return (&categoryRelation{}).WhereBetween(field, low, high)
+}
+
+func (_ CategoriesQuerying) WhereEq(field string, value interface{}) CategoryRelation {
+	return (&categoryRelation{}).WhereEq(field, value)
+}
+
+func (_ CategoriesQuerying) WhereIn(field string, values ...interface{}) CategoryRelation {
+	return (&categoryRelation{}).WhereIn(field, values...)
+}
+
+func (_ CategoriesQuerying) WhereLike(field, pattern string) CategoryRelation {
+	return (&categoryRelation{}).WhereLike(field, pattern)
+}
+
+func (_ CategoriesQuerying) WhereNot(field string, value interface{}) CategoryRelation {
+	return (&categoryRelation{}).WhereNot(field, value)
+}
+
+func (_ CategoriesQuerying) whereExpr() rel.Expr {
+	return (&categoryRelation{}).whereExpr()
+}
+
+func (_ CategoriesQuerying) joinExprs() []rel.Expr {
+	return (&categoryRelation{}).joinExprs()
+}
+
+// FindBySQL returns all the Categories selected by the given query
+func (q CategoriesQuerying) FindBySQL(ctx context.Context, db DB, query string, args ...interface{}) ([]*Category, error) {
+	var categories []*Category
+	err := q.EachBySQL(ctx, db, query, args, func(o *Category) error {
+		categories = append(categories, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// EachBySQL calls fn for each of the Categories selected by the given
+// query, as they're read from the database
+func (_ CategoriesQuerying) EachBySQL(ctx context.Context, db DB, query string, args []interface{}, fn func(*Category) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	row := &Category{}
+	row.persisted = true
+	fields, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	ptrs, err := row.pointersForFields(fields)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+
+		o := &Category{}
+		*o = *row
+		o.saved()
+
+		if err := fn(o); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// InsertAll inserts the records with as few statements as the limit on bind
+// parameters allows. Unlike Save, it doesn't validate the records or call
+// hooks. When a statement fails, the rows of the ones before it
+// stay inserted; use a Transaction to insert all or nothing.
+func (_ CategoriesQuerying) InsertAll(ctx context.Context, db DB, records []*Category) error {
+	return insertCategories(ctx, db, records, nil)
+}
+
+// UpsertAll inserts the records, or updates the rows that have the same
+// values for the conflict columns, which need a unique index. Those are the
+// primary key if none are given. Like InsertAll, it doesn't validate the
+// records or call hooks. The records are read back from the rows afterwards,
+// as existing rows keep their keys and creation timestamps.
+func (_ CategoriesQuerying) UpsertAll(ctx context.Context, db DB, records []*Category, conflictColumns ...string) error {
+	primaryKey := []string{"slug"}
+	if len(conflictColumns) == 0 {
+		conflictColumns = primaryKey
+	}
+	for _, column := range conflictColumns {
+		if (&Category{}).fieldPointerForColumn(column) == nil {
+			return errors.Errorf("unknown column %q", column)
+		}
+	}
+
+	onConflict := &rel.OnConflict{Columns: conflictColumns}
+	for _, column := range []string{"name"} {
+		if !containsColumn(conflictColumns, column) {
+			onConflict.Update = append(onConflict.Update, rel.Assignment{
+				Field: rel.Field{column},
+				Value: rel.Excluded{column},
+			})
+		}
+	}
+
+	return insertCategories(ctx, db, records, onConflict)
+}
+
+// readCategoriesRows copies the rows with the same values for the
+// columns into the records, as upserted rows can keep values of the existing
+// ones. The rows an upsert returned are used first, the others are selected.
+func readCategoriesRows(ctx context.Context, db DB, records, returned []*Category, columns []string) error {
+	keyOf := func(o *Category) string {
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			values[i] = o.valueForColumn(column)
+		}
+		key, err := json.Marshal(values)
+		if err != nil {
+			panic(err)
+		}
+		return string(key)
+	}
+
+	byKey := make(map[string]*Category, len(records))
+	for _, row := range returned {
+		byKey[keyOf(row)] = row
+	}
+	var missing []*Category
+	for _, o := range records {
+		if _, ok := byKey[keyOf(o)]; !ok {
+			missing = append(missing, o)
+		}
+	}
+
+	size := Dialect.MaxParams() / len(columns)
+	for len(missing) > 0 {
+		chunk := missing
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		missing = missing[len(chunk):]
+
+		matches := make(rel.Or, len(chunk))
+		for i, o := range chunk {
+			match := make(rel.And, len(columns))
+			for j, column := range columns {
+				match[j] = rel.Equality{
+					Field: rel.Field{"categories." + column},
+					Value: rel.BindParam{Value: o.valueForColumn(column)},
+				}
+			}
+			matches[i] = match
+		}
+		rows, err := (&categoryRelation{whereClause: []rel.Expr{matches}}).All(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			byKey[keyOf(row)] = row
+		}
+	}
+
+	for _, o := range records {
+		row, ok := byKey[keyOf(o)]
+		if !ok {
+			return errors.New("upserted row not found")
+		}
+		o.Slug = row.Slug
+		o.Name = row.Name
+		o.persisted = true
+		o.saved()
+	}
+	return nil
+}
+
+// insertCategories inserts the records in statements of as many rows as fit,
+// handling conflicts with onConflict if it's set. Upserted records are read back
+// from their rows.
+func insertCategories(ctx context.Context, db DB, records []*Category, onConflict *rel.OnConflict) error {
+	var columns []string
+	columns = append(columns, "slug")
+	columns = append(columns, "name")
+	size := Dialect.MaxParams() / len(columns)
+
+	for len(records) > 0 {
+		chunk := records
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		records = records[len(chunk):]
+
+		stmt := &rel.InsertStatement{
+			Dialect:    Dialect,
+			Table:      "categories",
+			Columns:    columns,
+			OnConflict: onConflict,
+		}
+		for _, o := range chunk {
+			row := make([]rel.Expr, 0, len(columns))
+			row = append(row, &rel.BindParam{Value: o.Slug})
+			row = append(row, &rel.BindParam{Value: o.Name})
+			stmt.Rows = append(stmt.Rows, row)
+		}
+
+		// Existing rows keep some of their values, so upserted rows are read back
+		if onConflict != nil {
+			var returned []*Category
+			if Dialect.Returning() {
+				stmt.Returning = categoryColumns
+				query, values := stmt.Build()
+				var err error
+				if returned, err = Categories().FindBySQL(ctx, db, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				query, values := stmt.Build()
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			}
+			if err := readCategoriesRows(ctx, db, chunk, returned, onConflict.Columns); err != nil {
+				return err
+			}
+			continue
+		}
+
+		query, values := stmt.Build()
+		if _, err := db.ExecContext(ctx, query, values...); err != nil {
+			return errors.Wrapf(err, "executing %q", query)
+		}
+
+		for _, o := range chunk {
+			o.persisted = true
+			o.saved()
+		}
+	}
+
+	return nil
+}
+
+// CountBySQL executes the given query, giving a count
+func (_ CategoriesQuerying) CountBySQL(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	var count int64
+	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+type categoryRelation struct {
+	fields      []string
+	joins       []rel.Expr
+	preloads    []string
+	whereClause []rel.Expr
+	orderValues []rel.Expr
+	limit       int64
+	offset      int64
+}
+
+func (q *categoryRelation) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	clauses := []rel.Expr{rel.Literal{Text: query, Params: args}}
+
+	stmt := &rel.UpdateStatement{
+		Dialect: Dialect,
+		Table:   "categories",
+		Wheres:  q.keyedWheres(),
+		Values:  clauses,
+	}
+
+	query, values := stmt.Build()
+	res, err := db.ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (q *categoryRelation) ToSQL() (query string, args []interface{}) {
+	return q.selectStatement().Build()
+}
+
+func (q *categoryRelation) selectStatement() *rel.SelectStatement {
+	return &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: q.columns(),
+		Table:   "categories",
+		Joins:   q.joins,
+		Wheres:  q.whereClause,
+		Orders:  q.orderValues,
+		Limit:   q.limit,
+		Offset:  q.offset,
+	}
+}
+
+// keyedWheres are the conditions for updating or deleting the relation's
+// records. Those statements can't join, so with joins the records are
+// matched by their primary key instead.
+func (q *categoryRelation) keyedWheres() []rel.Expr {
+	if len(q.joins) == 0 {
+		return q.whereClause
+	}
+
+	keys := rel.ExprList{
+		rel.Field{"categories.slug"},
+	}
+	// MySQL can't select from the table that's changed in a subquery, unless
+	// it's wrapped in a derived table
+	return []rel.Expr{rel.In{
+		Left: rel.Grouping{Expr: keys},
+		Right: rel.ExprList{&rel.SelectStatement{
+			Columns: []rel.Expr{
+				rel.Field{"keys.slug"},
+			},
+			Table: "keys",
+			From: &rel.SelectStatement{
+				Columns: keys,
+				Table:   "categories",
+				Joins:   q.joins,
+				Wheres:  q.whereClause,
+			},
+		}},
+	}}
+}
+
+func (q *categoryRelation) Count(ctx context.Context, db DB) (int64, error) {
+	var count int64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "COUNT"}, &count)
+	return count, err
+}
+
+func (q *categoryRelation) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{rel.Field{column}, rel.Aggregate{Func: "COUNT"}}
+	s.Groups = []rel.Expr{rel.Field{column}}
+	s.Orders = nil
+
+	query, args := s.Build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[interface{}]int64)
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		// Text can come back as bytes, which can't be a map key
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		counts[value] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (q *categoryRelation) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	var sum sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Float64, err
+}
+
+func (q *categoryRelation) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	var sum sql.NullInt64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Int64, err
+}
+
+func (q *categoryRelation) Average(ctx context.Context, db DB, column string) (float64, error) {
+	var avg sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "AVG", Expr: rel.Field{column}}, &avg)
+	return avg.Float64, err
+}
+
+func (q *categoryRelation) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MIN", Expr: rel.Field{column}}, dest)
+}
+
+func (q *categoryRelation) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MAX", Expr: rel.Field{column}}, dest)
+}
+
+// aggregate selects a single aggregate over the relation's records into dest
+func (q *categoryRelation) aggregate(ctx context.Context, db DB, aggregate rel.Aggregate, dest interface{}) error {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{aggregate}
+	// Ordering only makes sense for the records themselves
+	s.Orders = nil
+
+	query, args := s.Build()
+	// Aggregates lose the column's type, so SQLite returns times as text
+	switch dest.(type) {
+	case *time.Time, *sql.NullTime, **time.Time:
+		dest = timeScanner{dest: dest}
+	}
+	return db.QueryRowContext(ctx, query, args...).Scan(dest)
+}
+
+func (q *categoryRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	s := rel.DeleteStatement{
+		Dialect: Dialect,
+		Table:   "categories",
+		Wheres:  q.keyedWheres(),
+	}
+
+	query, args := s.Build()
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (q *categoryRelation) Where(value interface{}, args ...interface{}) CategoryRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, clauses...)
+
+	return q
+}
+
+func (q *categoryRelation) WhereEq(field string, value interface{}) CategoryRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Equality{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *categoryRelation) WhereBetween(field string, low, high interface{}) CategoryRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Between{
+		Field: rel.Field{field},
+		Low:   rel.BindParam{low},
+		High:  rel.BindParam{high},
+	})
+
+	return q
+}
+
+func (q *categoryRelation) WhereIn(field string, values ...interface{}) CategoryRelation {
+	q = q.clone()
+	params := make(rel.ExprList, len(values))
+	for i, value := range values {
+		params[i] = rel.BindParam{value}
+	}
+	q.whereClause = append(q.whereClause, rel.In{
+		Left:  rel.Field{field},
+		Right: params,
+	})
+
+	return q
+}
+
+func (q *categoryRelation) WhereLike(field, pattern string) CategoryRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Like{
+		Field:   rel.Field{field},
+		Pattern: rel.BindParam{pattern},
+	})
+
+	return q
+}
+
+func (q *categoryRelation) WhereNot(field string, value interface{}) CategoryRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.NotEqual{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *categoryRelation) Not(value interface{}, args ...interface{}) CategoryRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, rel.Not{Expr: rel.And(clauses)})
+
+	return q
+}
+
+func (q *categoryRelation) Or(other CategoryRelation) CategoryRelation {
+	if !sameJoins(q.joins, other.joinExprs()) {
+		panic("relation passed to Or must have the same joins")
+	}
+	q = q.clone()
+	q.whereClause = []rel.Expr{rel.Or{q.whereExpr(), other.whereExpr()}}
+
+	return q
+}
+
+// clone copies the relation, so the methods building on it leave it as it
+// is. The slices are capped, so appending to them copies them as well.
+func (q *categoryRelation) clone() *categoryRelation {
+	c := *q
+	c.fields = c.fields[:len(c.fields):len(c.fields)]
+	c.joins = c.joins[:len(c.joins):len(c.joins)]
+	c.preloads = c.preloads[:len(c.preloads):len(c.preloads)]
+	c.whereClause = c.whereClause[:len(c.whereClause):len(c.whereClause)]
+	c.orderValues = c.orderValues[:len(c.orderValues):len(c.orderValues)]
+	return &c
+}
+
+// whereExpr combines the conditions of the relation into a single expression
+func (q *categoryRelation) whereExpr() rel.Expr {
+	return rel.And(q.whereClause)
+}
+
+func (q *categoryRelation) joinExprs() []rel.Expr {
+	return q.joins
+}
+
+func (q *categoryRelation) Joins(associations ...string) CategoryRelation {
+	q = q.clone()
+	for _, association := range associations {
+		switch association {
+		case "posts":
+			q.joins = append(q.joins, rel.InnerJoin{
+				Table: "posts",
+				On: rel.Equality{
+					Field: rel.Field{"posts.category_id"},
+					Value: rel.Field{"categories.slug"},
+				},
+			})
+		default:
+			panic(fmt.Sprintf("unknown association %q", association))
+		}
+	}
+
+	return q
+}
+
+func (q *categoryRelation) Limit(limit int64) CategoryRelation {
+	q = q.clone()
+	q.limit = limit
+	return q
+}
+
+func (q *categoryRelation) New() *Category {
+	o := &Category{}
+	for _, w := range q.whereClause {
+		if eq, ok := w.(rel.Equality); ok {
+			name := strings.TrimPrefix(eq.Field.Name, "categories.")
+			if eq.Value == nil {
+				o.assignField(name, nil)
+			} else if bind, ok := eq.Value.(rel.BindParam); ok {
+				o.assignField(name, bind.Value)
+			}
+		}
+	}
+
+	return o
+}
+
+func (q *categoryRelation) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*CategoryPage, error) {
+	if pageSize <= 0 {
+		return nil, errors.Errorf("invalid page size %d", pageSize)
+	}
+	if q.limit != 0 || q.offset != 0 {
+		return nil, errors.New("Paginate can't be used with a limit or offset")
+	}
+
+	keys, err := parseOrder("categories", q.orderValues)
+	if err != nil {
+		return nil, err
+	}
+	edge := &Category{}
+	for _, key := range keys {
+		if !edge.sortable(key.column) {
+			return nil, errors.Errorf("can't paginate by %q", key.column)
+		}
+	}
+	keys = appendSortKey(keys, "slug")
+
+	page := q.clone()
+	page.limit = int64(pageSize) + 1
+	// The cursors need the values of the keys
+	if page.fields != nil {
+		for _, key := range keys {
+			page.fields = append(page.fields, "categories."+key.column)
+		}
+	}
+	var position cursorPosition
+	if cursor != "" {
+		if position, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			value, ok := position.Values[key.column]
+			if !ok || json.Unmarshal(value, edge.fieldPointerForColumn(key.column)) != nil {
+				return nil, errors.Errorf("invalid cursor %q", cursor)
+			}
+		}
+		page.whereClause = append(page.whereClause, seek("categories", keys, edge.valueForColumn, position.Before))
+	}
+	page.orderValues = nil
+	for _, key := range keys {
+		field := rel.Field{Name: "categories." + key.column}
+		if key.descending != position.Before {
+			page.orderValues = append(page.orderValues, rel.Descending{Expr: field})
+		} else {
+			page.orderValues = append(page.orderValues, rel.Ascending{Expr: field})
+		}
+	}
+
+	records, err := page.All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	more := len(records) > pageSize
+	if more {
+		records = records[:pageSize]
+	}
+	if position.Before {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	result := &CategoryPage{Records: records}
+	if len(records) == 0 {
+		return result, nil
+	}
+	if more && !position.Before || cursor != "" && position.Before {
+		result.Next = records[len(records)-1].cursor(keys, false)
+	}
+	if more && position.Before || cursor != "" && !position.Before {
+		result.Previous = records[0].cursor(keys, true)
+	}
+	return result, nil
+}
+
+func (q *categoryRelation) Preload(associations ...string) CategoryRelation {
+	q = q.clone()
+	q.preloads = append(q.preloads, associations...)
+	return q
+}
+
+func (q *categoryRelation) Select(fields ...string) CategoryRelation {
+	q = q.clone()
+	q.fields = append(q.fields, fields...)
+	return q
+}
+
+func (q *categoryRelation) Offset(offset int64) CategoryRelation {
+	q = q.clone()
+	q.offset = offset
+	return q
+}
+
+func (q *categoryRelation) columns() []rel.Expr {
+	if q.fields == nil {
+		return []rel.Expr{
+			rel.Field{"categories.slug"},
+			rel.Field{"categories.name"},
+		}
+	}
+
+	columns := make([]rel.Expr, 0, len(q.fields))
+	for _, field := range q.fields {
+		columns = append(columns, &rel.Literal{Text: field})
+	}
+	return columns
+}
+
+func (q *categoryRelation) All(ctx context.Context, db DB) ([]*Category, error) {
+	query, args := q.ToSQL()
+	records, err := Categories().FindBySQL(ctx, db, query, args...)
+	if err != nil || len(q.preloads) == 0 {
+		return records, err
+	}
+
+	if err := preloadCategories(ctx, db, records, q.preloads); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (q *categoryRelation) Take(ctx context.Context, db DB) (*Category, error) {
+	q = q.clone()
+	q.limit = 1
+	os, err := q.All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(os) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return os[0], nil
+}
+
+func (q *categoryRelation) Each(ctx context.Context, db DB, fn func(*Category) error) error {
+	if len(q.preloads) > 0 {
+		return errors.New("Each can't preload associations, use FindInBatches")
+	}
+	query, args := q.ToSQL()
+	return Categories().EachBySQL(ctx, db, query, args, fn)
+}
+
+func (q *categoryRelation) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Category) error) error {
+	if batchSize <= 0 {
+		return errors.Errorf("invalid batch size %d", batchSize)
+	}
+	if len(q.orderValues) > 0 || q.limit != 0 || q.offset != 0 {
+		return errors.New("FindInBatches can't be used with an order, limit or offset")
+	}
+
+	batch := q.clone()
+	batch.limit = int64(batchSize)
+	// The next batch starts after the key of the last record, so it has to be
+	// selected
+	if batch.fields != nil {
+		batch.fields = append(batch.fields, "categories.slug")
+	}
+	batch.orderValues = append(batch.orderValues, rel.Ascending{Expr: rel.Field{"categories.slug"}})
+	for {
+		records, err := batch.All(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+
+		batch = batch.clone()
+		batch.whereClause = append(q.whereClause[:len(q.whereClause):len(q.whereClause)], records[len(records)-1].afterKey())
+	}
+}
+
+func (q *categoryRelation) Find(ctx context.Context, db DB, slug string) (*Category, error) {
+	return q.WhereEq("categories.slug", slug).Take(ctx, db)
+}
+
+func (q *categoryRelation) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Category, error) {
+	return q.Where(query, args...).Take(ctx, db)
+}
+
+func (q *categoryRelation) First(ctx context.Context, db DB) (*Category, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Ascending{Expr: rel.Field{"categories.slug"}})
+	return q.Take(ctx, db)
+}
+
+func (q *categoryRelation) Last(ctx context.Context, db DB) (*Category, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Descending{Expr: rel.Field{"categories.slug"}})
+	return q.Take(ctx, db)
+}
+
+func (q *categoryRelation) Order(query string, args ...string) CategoryRelation {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, &rel.Literal{Text: query})
+
+	for i := 0; i < len(args); i++ {
+		q.orderValues = append(q.orderValues, &rel.Literal{Text: args[i]})
+	}
+
+	return q
+}
+
+// preloadCategories fills the association caches of the records for the
+// given association paths
+func preloadCategories(ctx context.Context, db DB, records []*Category, paths []string) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// The nested paths are preloaded along with their first association
+	var names []string
+	nested := make(map[string][]string)
+	for _, path := range paths {
+		name, rest := path, ""
+		if i := strings.Index(path, "."); i >= 0 {
+			name, rest = path[:i], path[i+1:]
+		}
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = []string{}
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+
+	for _, name := range names {
+		switch name {
+		case "Posts":
+			var keys []interface{}
+			seen := make(map[string]bool, len(records))
+			for _, o := range records {
+				if !seen[o.Slug] {
+					seen[o.Slug] = true
+					keys = append(keys, o.Slug)
+				}
+			}
+
+			byKey := make(map[string][]*Post)
+			for _, chunk := range keyChunks(keys) {
+				children, err := Posts().WhereIn("posts.category_id", chunk...).Preload(nested[name]...).All(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, c := range children {
+					byKey[c.CategoryID.String] = append(byKey[c.CategoryID.String], c)
+				}
+			}
+			for _, o := range records {
+				o.associations.Posts.records = byKey[o.Slug]
+				o.associations.Posts.loaded = true
+			}
+		default:
+			return errors.Errorf("categories: unknown association %q", name)
+		}
+	}
+
+	return nil
+}
+
+type Post struct {
+	// ID ...
+	ID int64
+
+	// UserID ...
+	UserID int64
+
+	// CategoryID ...
+	CategoryID sql.NullString
+
+	// Body ...
+	Body string
+
+	// CreatedAt ...
+	CreatedAt time.Time
+
+	// UpdatedAt ...
+	UpdatedAt time.Time
+
+	// If true, then this record exists in the DB
+	persisted bool
+	deleted   bool
+
+	old struct {
+		// ID ...
+		ID int64
+
+		// UserID ...
+		UserID int64
+
+		// CategoryID ...
+		CategoryID sql.NullString
+
+		// Body ...
+		Body string
+
+		// CreatedAt ...
+		CreatedAt time.Time
+
+		// UpdatedAt ...
+		UpdatedAt time.Time
+	}
+
+	associations struct {
+		PostTags struct {
+			loaded  bool
+			records []*PostTag
+		}
+
+		Users struct {
+			loaded bool
+			record *User
+		}
+
+		Categories struct {
+			loaded bool
+			record *Category
+		}
+	}
+}
+
+func (o *Post) PostTags() PostHasManyPostTagsCollection {
+	return (*postHasManyPostTagsCollection)(o)
+}
+
+type PostHasManyPostTagsCollection interface {
+	PostTagRelation
+
+	// Loaded specifies whether the association has been loaded
+	Loaded() bool
+
+	// Reset clears out the association
+	Reset()
+}
+
+type postHasManyPostTagsCollection Post
+
+func (o *postHasManyPostTagsCollection) relation() PostTagRelation {
+	return PostTags().WhereEq("post_tags.post_id", o.ID)
+}
+
+func (o *postHasManyPostTagsCollection) Loaded() bool {
+	return o.associations.PostTags.loaded
+}
+
+func (o *postHasManyPostTagsCollection) Reset() {
+	o.associations.PostTags.records = nil
+	o.associations.PostTags.loaded = false
+}
+
+func (o *postHasManyPostTagsCollection) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return o.relation().Average(ctx, db, column)
+}
+
+func (o *postHasManyPostTagsCollection) Count(ctx context.Context, db DB) (int64, error) {
+	return o.relation().Count(ctx, db)
+}
+
+func (o *postHasManyPostTagsCollection) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return o.relation().CountBy(ctx, db, column)
+}
+
+func (o *postHasManyPostTagsCollection) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	return o.relation().DeleteAll(ctx, db)
+}
+
+func (o *postHasManyPostTagsCollection) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return o.relation().Maximum(ctx, db, column, dest)
+}
+
+func (o *postHasManyPostTagsCollection) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return o.relation().Minimum(ctx, db, column, dest)
+}
+
+func (o *postHasManyPostTagsCollection) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return o.relation().Sum(ctx, db, column)
+}
+
+func (o *postHasManyPostTagsCollection) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return o.relation().SumInt(ctx, db, column)
+}
+
+func (o *postHasManyPostTagsCollection) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	return o.relation().UpdateAll(ctx, db, query, args...)
+}
+
+func (o *postHasManyPostTagsCollection) All(ctx context.Context, db DB) ([]*PostTag, error) {
+	if o.Loaded() {
+		return o.associations.PostTags.records, nil
+	}
+
+	records, err := o.relation().All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	o.associations.PostTags.records = records
+	o.associations.PostTags.loaded = true
+
+	return records, nil
+}
+
+func (o *postHasManyPostTagsCollection) Each(ctx context.Context, db DB, fn func(*PostTag) error) error {
+	return o.relation().Each(ctx, db, fn)
+}
+
+func (o *postHasManyPostTagsCollection) Find(ctx context.Context, db DB, postID int64, tag string) (*PostTag, error) {
+	return o.relation().Find(ctx, db, postID, tag)
+}
+
+func (o *postHasManyPostTagsCollection) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*PostTag, error) {
+	return o.relation().FindBy(ctx, db, query, args...)
+}
+
+func (o *postHasManyPostTagsCollection) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*PostTag) error) error {
+	return o.relation().FindInBatches(ctx, db, batchSize, fn)
+}
+
+func (o *postHasManyPostTagsCollection) First(ctx context.Context, db DB) (*PostTag, error) {
+	return o.relation().First(ctx, db)
+}
+
+func (o *postHasManyPostTagsCollection) Joins(associations ...string) PostTagRelation {
+	return o.relation().Joins(associations...)
+}
+
+func (o *postHasManyPostTagsCollection) Last(ctx context.Context, db DB) (*PostTag, error) {
+	return o.relation().Last(ctx, db)
+}
+
+func (o *postHasManyPostTagsCollection) Limit(limit int64) PostTagRelation {
+	return o.relation().Limit(limit)
+}
+
+func (o *postHasManyPostTagsCollection) New() *PostTag {
+	return o.relation().New()
+}
+
+func (o *postHasManyPostTagsCollection) Not(value interface{}, args ...interface{}) PostTagRelation {
+	return o.relation().Not(value, args...)
+}
+
+func (o *postHasManyPostTagsCollection) Offset(offset int64) PostTagRelation {
+	return o.relation().Offset(offset)
+}
+
+func (o *postHasManyPostTagsCollection) Or(other PostTagRelation) PostTagRelation {
+	return o.relation().Or(other)
+}
+
+func (o *postHasManyPostTagsCollection) Order(query string, args ...string) PostTagRelation {
+	return o.relation().Order(query, args...)
+}
+
+func (o *postHasManyPostTagsCollection) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostTagPage, error) {
+	return o.relation().Paginate(ctx, db, cursor, pageSize)
+}
+
+func (o *postHasManyPostTagsCollection) Preload(associations ...string) PostTagRelation {
+	return o.relation().Preload(associations...)
+}
+
+func (o *postHasManyPostTagsCollection) Select(fields ...string) PostTagRelation {
+	return o.relation().Select(fields...)
+}
+
+func (o *postHasManyPostTagsCollection) Take(ctx context.Context, db DB) (*PostTag, error) {
+	return o.relation().Take(ctx, db)
+}
+
+func (o *postHasManyPostTagsCollection) Where(value interface{}, args ...interface{}) PostTagRelation {
+	return o.relation().Where(value, args...)
+}
+
+func (o *postHasManyPostTagsCollection) WhereBetween(field string, low, high interface{}) PostTagRelation {
+	return o.relation().WhereBetween(field, low, high)
+}
+
+func (o *postHasManyPostTagsCollection) WhereEq(field string, value interface{}) PostTagRelation {
+	return o.relation().WhereEq(field, value)
+}
+
+func (o *postHasManyPostTagsCollection) WhereIn(field string, values ...interface{}) PostTagRelation {
+	return o.relation().WhereIn(field, values...)
+}
+
+func (o *postHasManyPostTagsCollection) WhereLike(field, pattern string) PostTagRelation {
+	return o.relation().WhereLike(field, pattern)
+}
+
+func (o *postHasManyPostTagsCollection) WhereNot(field string, value interface{}) PostTagRelation {
+	return o.relation().WhereNot(field, value)
+}
+
+func (o *postHasManyPostTagsCollection) whereExpr() rel.Expr {
+	return o.relation().whereExpr()
+}
+
+func (o *postHasManyPostTagsCollection) joinExprs() []rel.Expr {
+	return o.relation().joinExprs()
+}
+
+func (o *Post) User(ctx context.Context, db DB) (*User, error) {
+	if o.associations.Users.loaded {
+		return o.associations.Users.record, nil
+	}
+
+	record, err := Users().Find(ctx, db, o.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	o.associations.Users.record = record
+	o.associations.Users.loaded = true
+
+	return record, nil
+}
+
+func (o *Post) Category(ctx context.Context, db DB) (*Category, error) {
+	if o.associations.Categories.loaded {
+		return o.associations.Categories.record, nil
+	}
+
+	if !o.CategoryID.Valid {
+		return nil, nil
+	}
+
+	record, err := Categories().Find(ctx, db, o.CategoryID.String)
+	if err != nil {
+		return nil, err
+	}
+
+	o.associations.Categories.record = record
+	o.associations.Categories.loaded = true
+
+	return record, nil
+}
+
+func (o *Post) Save(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+
+	if err := o.Validate(ctx, db); err != nil {
+		return err
+	}
+
+	if hook, ok := interface{}(o).(BeforeSaveHook); ok {
+		if err := hook.BeforeSave(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	if o.persisted {
+		if hook, ok := interface{}(o).(BeforeUpdateHook); ok {
+			if err := hook.BeforeUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		stmt := &rel.UpdateStatement{
+			Dialect: Dialect,
+			Table:   "posts",
+			Wheres:  o.keyWheres(),
+		}
+
+		if o.ID != o.old.ID {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"id"},
+				Value: &rel.BindParam{
+					Value: o.ID,
+				},
+			})
+		}
+
+		if o.UserID != o.old.UserID {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"user_id"},
+				Value: &rel.BindParam{
+					Value: o.UserID,
+				},
+			})
+		}
+
+		if o.CategoryID != o.old.CategoryID {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"category_id"},
+				Value: &rel.BindParam{
+					Value: o.CategoryID,
+				},
+			})
+		}
+
+		if o.Body != o.old.Body {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"body"},
+				Value: &rel.BindParam{
+					Value: o.Body,
+				},
+			})
+		}
+
+		if o.CreatedAt != o.old.CreatedAt {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"created_at"},
+				Value: &rel.BindParam{
+					Value: o.CreatedAt,
+				},
+			})
+		}
+
+		if o.UpdatedAt != o.old.UpdatedAt {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"updated_at"},
+				Value: &rel.BindParam{
+					Value: o.UpdatedAt,
+				},
+			})
+		}
+
+		// Only bump the timestamps when something else changed
+		if len(stmt.Values) > 0 {
+			now := Now()
+			if o.UpdatedAt == o.old.UpdatedAt {
+				o.UpdatedAt = now
+				stmt.Values = append(stmt.Values, rel.Assignment{
+					Field: rel.Field{"updated_at"},
+					Value: &rel.BindParam{
+						Value: o.UpdatedAt,
+					},
+				})
+			}
+		}
+
+		// There's nothing to write when no field changed
+		if len(stmt.Values) > 0 {
+			if Dialect.Returning() {
+				stmt.Returning = postColumns
+			}
+			query, values := stmt.Build()
+			if Dialect.Returning() {
+				if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+				o.saved()
+				if err := o.reload(ctx, db); err != nil {
+					return err
+				}
+			}
+		}
+		if hook, ok := interface{}(o).(AfterUpdateHook); ok {
+			if err := hook.AfterUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+	} else {
+		if hook, ok := interface{}(o).(BeforeCreateHook); ok {
+			if err := hook.BeforeCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		now := Now()
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = now
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = now
+		}
+
+		stmt := &rel.InsertStatement{
+			Dialect: Dialect,
+			Table:   "posts",
+		}
+
+		if o.ID != 0 {
+			stmt.Columns = append(stmt.Columns, "id")
+			stmt.Values = append(stmt.Values, &rel.BindParam{
+				Value: o.ID,
+			})
+		}
+		stmt.Columns = append(stmt.Columns, "user_id")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.UserID,
+		})
+		stmt.Columns = append(stmt.Columns, "category_id")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.CategoryID,
+		})
+		stmt.Columns = append(stmt.Columns, "body")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.Body,
+		})
+		stmt.Columns = append(stmt.Columns, "created_at")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.CreatedAt,
+		})
+		stmt.Columns = append(stmt.Columns, "updated_at")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.UpdatedAt,
+		})
+
+		if Dialect.Returning() {
+			stmt.Returning = postColumns
+		}
+		query, values := stmt.Build()
+		if Dialect.Returning() {
+			if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+		} else {
+			res, err := db.ExecContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+
+			if o.ID == 0 {
+				o.ID, err = res.LastInsertId()
+				if err != nil {
+					return err
+				}
+			}
+			o.saved()
+			if err := o.reload(ctx, db); err != nil {
+				return err
+			}
+		}
+		if hook, ok := interface{}(o).(AfterCreateHook); ok {
+			if err := hook.AfterCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hook, ok := interface{}(o).(AfterSaveHook); ok {
+		if err := hook.AfterSave(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upsert inserts the record, or updates the row that has the same values for
+// the conflict columns, see UpsertAll
+func (o *Post) Upsert(ctx context.Context, db DB, conflictColumns ...string) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	return Posts().UpsertAll(ctx, db, []*Post{o}, conflictColumns...)
+}
+
+// Validate checks the values against the validations in the schema. It
+// returns ValidationErrors when they don't pass.
+func (o *Post) Validate(ctx context.Context, db DB) error {
+	errs := ValidationErrors{}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Touch sets the update timestamps to the current time and saves just those,
+// without validating the record or calling hooks
+func (o *Post) Touch(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	if !o.persisted {
+		return fmt.Errorf("record not persisted")
+	}
+
+	stmt := &rel.UpdateStatement{
+		Dialect: Dialect,
+		Table:   "posts",
+		Wheres:  o.keyWheres(),
+	}
+	now := Now()
+	o.UpdatedAt = now
+	stmt.Values = append(stmt.Values, rel.Assignment{
+		Field: rel.Field{"updated_at"},
+		Value: &rel.BindParam{
+			Value: o.UpdatedAt,
+		},
+	})
+
+	query, values := stmt.Build()
+	if _, err := db.ExecContext(ctx, query, values...); err != nil {
+		return errors.Wrapf(err, "executing %q", query)
+	}
+
+	o.old.UpdatedAt = o.UpdatedAt
+	return nil
+}
+
+// IsPersisted is true once the record has been loaded or saved
+func (o *Post) IsPersisted() bool {
+	return o.persisted
+}
+
+// IsDeleted is true once the record has been deleted
+func (o *Post) IsDeleted() bool {
+	return o.deleted
+}
+
+// IsChanged is true if any of the fields differ from the row the record was
+// loaded or last saved as. For new records, that's any field that isn't zero.
+func (o *Post) IsChanged() bool {
+	return o.ID != o.old.ID ||
+		o.UserID != o.old.UserID ||
+		o.CategoryID != o.old.CategoryID ||
+		o.Body != o.old.Body ||
+		o.CreatedAt != o.old.CreatedAt ||
+		o.UpdatedAt != o.old.UpdatedAt
+}
+
+// ChangedColumns returns the columns of the fields that changed, in the order
+// of the table
+func (o *Post) ChangedColumns() []string {
+	var columns []string
+	if o.ID != o.old.ID {
+		columns = append(columns, "id")
+	}
+	if o.UserID != o.old.UserID {
+		columns = append(columns, "user_id")
+	}
+	if o.CategoryID != o.old.CategoryID {
+		columns = append(columns, "category_id")
+	}
+	if o.Body != o.old.Body {
+		columns = append(columns, "body")
+	}
+	if o.CreatedAt != o.old.CreatedAt {
+		columns = append(columns, "created_at")
+	}
+	if o.UpdatedAt != o.old.UpdatedAt {
+		columns = append(columns, "updated_at")
+	}
+	return columns
+}
+
+// Changes returns the old and new value of the changed fields by column
+func (o *Post) Changes() map[string][2]interface{} {
+	changes := map[string][2]interface{}{}
+	if o.ID != o.old.ID {
+		changes["id"] = [2]interface{}{o.old.ID, o.ID}
+	}
+	if o.UserID != o.old.UserID {
+		changes["user_id"] = [2]interface{}{o.old.UserID, o.UserID}
+	}
+	if o.CategoryID != o.old.CategoryID {
+		changes["category_id"] = [2]interface{}{o.old.CategoryID, o.CategoryID}
+	}
+	if o.Body != o.old.Body {
+		changes["body"] = [2]interface{}{o.old.Body, o.Body}
+	}
+	if o.CreatedAt != o.old.CreatedAt {
+		changes["created_at"] = [2]interface{}{o.old.CreatedAt, o.CreatedAt}
+	}
+	if o.UpdatedAt != o.old.UpdatedAt {
+		changes["updated_at"] = [2]interface{}{o.old.UpdatedAt, o.UpdatedAt}
+	}
+	return changes
+}
+
+// Restore reverts the fields to the row the record was loaded or last saved
+// as, undoing the changes
+func (o *Post) Restore() {
+	o.ID = o.old.ID
+	o.UserID = o.old.UserID
+	o.CategoryID = o.old.CategoryID
+	o.Body = o.old.Body
+	o.CreatedAt = o.old.CreatedAt
+	o.UpdatedAt = o.old.UpdatedAt
+}
+
+// Reload reads the record's row again and resets the association caches.
+// It returns ErrNotFound if the row no longer exists.
+func (o *Post) Reload(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	if !o.persisted {
+		return fmt.Errorf("record not persisted")
+	}
+	if err := o.reload(ctx, db); err != nil {
+		return err
+	}
+	var reset Post
+	o.associations = reset.associations
+	return nil
+}
+
+// saved records that the fields match the row
+func (o *Post) saved() {
+	o.old.ID = o.ID
+	o.old.UserID = o.UserID
+	o.old.CategoryID = o.CategoryID
+	o.old.Body = o.Body
+	o.old.CreatedAt = o.CreatedAt
+	o.old.UpdatedAt = o.UpdatedAt
+}
+
+// postColumns are the columns Save reads back after writing a row
+var postColumns = []string{"id", "user_id", "category_id", "body", "created_at", "updated_at"}
+
+// scan reads the row's columns into the record, which then matches it
+func (o *Post) scan(row *sql.Row) error {
+	ptrs, err := o.pointersForFields(postColumns)
+	if err != nil {
+		return err
+	}
+	if err := row.Scan(ptrs...); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	o.saved()
+	return nil
+}
+
+// reload selects the record's row again, to see the values the database set
+// when the dialect can't return them from the insert or update
+func (o *Post) reload(ctx context.Context, db DB) error {
+	stmt := &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: (&postRelation{}).columns(),
+		Table:   "posts",
+		Wheres:  o.keyWheres(),
+	}
+	query, values := stmt.Build()
+	return o.scan(db.QueryRowContext(ctx, query, values...))
+}
+
+func (o *Post) Delete(ctx context.Context, db DB) error {
+	if hook, ok := interface{}(o).(BeforeDeleteHook); ok {
+		if err := hook.BeforeDelete(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	_, err := (&postRelation{whereClause: o.keyWheres()}).DeleteAll(ctx, db)
+	if err != nil {
+		return err
+	}
+	o.deleted = true
+	if hook, ok := interface{}(o).(AfterDeleteHook); ok {
+		if err := hook.AfterDelete(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyWheres matches the record's row. Once persisted, that's by the key it
+// was loaded or last saved with, so changes to the key are saved too.
+func (o *Post) keyWheres() []rel.Expr {
+	if !o.persisted {
+		return []rel.Expr{
+			rel.Equality{
+				Field: rel.Field{"id"},
+				Value: rel.BindParam{Value: o.ID},
+			},
+		}
+	}
+
+	return []rel.Expr{
+		rel.Equality{
+			Field: rel.Field{"id"},
+			Value: rel.BindParam{Value: o.old.ID},
+		},
+	}
+}
+
+// afterKey matches the rows ordered after the record by primary key
+func (o *Post) afterKey() rel.Expr {
+	return rel.Or{
+		rel.And{
+			rel.GreaterThan{
+				Field: rel.Field{"posts.id"},
+				Value: rel.BindParam{Value: o.ID},
+			},
+		},
+	}
+}
+
+// cursor is the position of the record in the order of the keys
+func (o *Post) cursor(keys []sortKey, before bool) Cursor {
+	position := cursorPosition{Before: before, Values: make(map[string]json.RawMessage, len(keys))}
+	for _, key := range keys {
+		value, err := json.Marshal(o.valueForColumn(key.column))
+		if err != nil {
+			panic(err)
+		}
+		position.Values[key.column] = value
+	}
+	return position.encode()
+}
+
+func (o *Post) fieldPointerForColumn(column string) interface{} {
+	switch column {
+	case "id":
+		return &o.ID
+	case "user_id":
+		return &o.UserID
+	case "category_id":
+		return &o.CategoryID
+	case "body":
+		return &o.Body
+	case "created_at":
+		return &o.CreatedAt
+	case "updated_at":
+		return &o.UpdatedAt
+	default:
+		return nil
+	}
+}
+
+// sortable is true for the columns Paginate can order by, which are the ones
+// that can't be NULL
+func (o *Post) sortable(column string) bool {
+	switch column {
+	case "id":
+		return true
+	case "user_id":
+		return true
+	case "body":
+		return true
+	case "created_at":
+		return true
+	case "updated_at":
+		return true
+	default:
+		return false
+	}
+}
+
+func (o *Post) valueForColumn(column string) interface{} {
+	switch column {
+	case "id":
+		return o.ID
+	case "user_id":
+		return o.UserID
+	case "category_id":
+		return o.CategoryID
+	case "body":
+		return o.Body
+	case "created_at":
+		return o.CreatedAt
+	case "updated_at":
+		return o.UpdatedAt
+	default:
+		return nil
+	}
+}
+
+func (o *Post) pointersForFields(fields []string) ([]interface{}, error) {
+	pointers := make([]interface{}, len(fields))
+	for i, field := range fields {
+		ptr := o.fieldPointerForColumn(field)
+		if ptr == nil {
+			return nil, fmt.Errorf("unknown column %q", field)
+		}
+		pointers[i] = ptr
+		switch ptr.(type) {
+		case *time.Time, *sql.NullTime, **time.Time:
+			pointers[i] = timeScanner{dest: ptr}
+		}
+	}
+	return pointers, nil
+}
+
+// assignField sets the field to the value.
+// It returns an error if the field doesn't exist or the value is the wrong type.
+func (o *Post) assignField(name string, value interface{}) error {
+	switch name {
+	case "id":
+		switch v := value.(type) {
+		case int64:
+			o.ID = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "user_id":
+		switch v := value.(type) {
+		case int64:
+			o.UserID = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "category_id":
+		switch v := value.(type) {
+		case nil:
+			o.CategoryID = sql.NullString{}
+		case string:
+			o.CategoryID = sql.NullString{String: v, Valid: true}
+		case sql.NullString:
+			o.CategoryID = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "body":
+		switch v := value.(type) {
+		case string:
+			o.Body = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "created_at":
+		switch v := value.(type) {
+		case time.Time:
+			o.CreatedAt = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	case "updated_at":
+		switch v := value.(type) {
+		case time.Time:
+			o.UpdatedAt = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
+
+		return nil
+	default:
+		return errors.Errorf("unknown field: %s", name)
+	}
+}
+
+// PostPage is a page of Posts, see Paginate
+type PostPage struct {
+	Records []*Post
+
+	// Next is the cursor for the following page, empty if this is the last one
+	Next Cursor
+
+	// Previous is the cursor for the preceding page, empty if this is the first one
+	Previous Cursor
+}
+
+// PostRelation builds a query for Posts. Relations are
+// immutable: each method returns a new relation, so a relation can be reused
+// as the base for other queries, and shared between goroutines.
+type PostRelation interface {
+	Relation
+
+	// All ...
+	All(ctx context.Context, db DB) ([]*Post, error)
+
+	// Each calls fn for each of the records as they're read from the
+	// database, without loading them all at once. The rows stay open while fn
+	// runs, so some drivers don't let fn query the same connection; use
+	// FindInBatches for that. Associations can't be preloaded.
+	Each(ctx context.Context, db DB, fn func(*Post) error) error
+
+	// Find ...
+	Find(ctx context.Context, db DB, id int64) (*Post, error)
+
+	// FindBy ...
+	FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error)
+
+	// FindInBatches calls fn with batches of up to batchSize records, ordered
+	// by primary key. Each batch is selected after the last key of the
+	// previous one, so the relation can't have an order, limit or offset.
+	FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Post) error) error
+
+	// First ...
+	First(ctx context.Context, db DB) (*Post, error)
+
+	// Joins adds an INNER JOIN for each of the associations, given by the
+	// associated table's name. The records are selected once for every match.
+	Joins(associations ...string) PostRelation
+
+	// Last ...
+	Last(ctx context.Context, db DB) (*Post, error)
+
+	// Limit ...
+	Limit(limit int64) PostRelation
+
+	// New creates a Post populated with the scope of the relation
+	New() *Post
+
+	// Not adds the negation of a condition, given like in Where
+	Not(value interface{}, args ...interface{}) PostRelation
+
+	// Offset ...
+	Offset(offset int64) PostRelation
+
+	// Or selects the records matching the conditions of either relation. It
+	// panics when the relations don't have the same joins.
+	Or(other PostRelation) PostRelation
+
+	// Order ...
+	Order(query string, args ...string) PostRelation
+
+	// Paginate selects the page of up to pageSize records at the cursor. The
+	// pages follow the order of the relation, which needs to be by columns, and
+	// then the primary key. Pages are found by the values at their edges
+	// instead of by offset, so they stay fast and don't skip or repeat records
+	// when others are added or removed. Nullable columns can't be ordered by,
+	// as NULLs don't compare.
+	Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostPage, error)
+
+	// Preload loads the named associations of the selected records with one
+	// query per association. Nested associations are separated by dots, like
+	// "Posts.PostTags".
+	Preload(associations ...string) PostRelation
+
+	// Select ...
+	Select(fields ...string) PostRelation
+
+	// Take ...
+	Take(ctx context.Context, db DB) (*Post, error)
+
+	// Where ...
+	Where(value interface{}, args ...interface{}) PostRelation
+
+	// WhereBetween adds a condition that the field is between low and high, inclusive
+	WhereBetween(field string, low, high interface{}) PostRelation
+
+	// WhereEq ...
+	WhereEq(field string, value interface{}) PostRelation
+
+	// WhereIn adds a condition that the field has one of the values
+	WhereIn(field string, values ...interface{}) PostRelation
+
+	// WhereLike adds a condition that the field matches the LIKE pattern, see rel.Like
+	WhereLike(field, pattern string) PostRelation
+
+	// WhereNot adds a condition that the field doesn't equal the value, or isn't NULL for nil
+	WhereNot(field string, value interface{}) PostRelation
+
+	whereExpr() rel.Expr
+	joinExprs() []rel.Expr
+}
+
+// PostsQuerying gives you access to Posts
+type PostsQuerying struct{}
+
+// PostsQuerying gives you access to Posts
+func Posts() PostsQuerying {
+	return PostsQuerying{}
+}
+
+func (_ PostsQuerying) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return (&postRelation{}).Average(ctx, db, column)
+}
+
+func (_ PostsQuerying) Count(ctx context.Context, db DB) (int64, error) {
+	return (&postRelation{}).Count(ctx, db)
+}
+
+func (_ PostsQuerying) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return (&postRelation{}).CountBy(ctx, db, column)
+}
+
+func (_ PostsQuerying) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	return (&postRelation{}).DeleteAll(ctx, db)
+}
+
+func (_ PostsQuerying) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&postRelation{}).Maximum(ctx, db, column, dest)
+}
+
+func (_ PostsQuerying) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&postRelation{}).Minimum(ctx, db, column, dest)
+}
+
+func (_ PostsQuerying) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return (&postRelation{}).Sum(ctx, db, column)
+}
+
+func (_ PostsQuerying) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return (&postRelation{}).SumInt(ctx, db, column)
+}
+
+func (_ PostsQuerying) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	return (&postRelation{}).UpdateAll(ctx, db, query, args...)
+}
+
+func (_ PostsQuerying) All(ctx context.Context, db DB) ([]*Post, error) {
+	return (&postRelation{}).All(ctx, db)
+}
+
+func (_ PostsQuerying) Each(ctx context.Context, db DB, fn func(*Post) error) error {
+	return (&postRelation{}).Each(ctx, db, fn)
+}
+
+func (_ PostsQuerying) Find(ctx context.Context, db DB, id int64) (*Post, error) {
+	return (&postRelation{}).Find(ctx, db, id)
+}
+
+func (_ PostsQuerying) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error) {
+	return (&postRelation{}).FindBy(ctx, db, query, args...)
+}
+
+func (_ PostsQuerying) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Post) error) error {
+	return (&postRelation{}).FindInBatches(ctx, db, batchSize, fn)
+}
+
+func (_ PostsQuerying) First(ctx context.Context, db DB) (*Post, error) {
+	return (&postRelation{}).First(ctx, db)
+}
+
+func (_ PostsQuerying) Joins(associations ...string) PostRelation {
+	return (&postRelation{}).Joins(associations...)
+}
+
+func (_ PostsQuerying) Last(ctx context.Context, db DB) (*Post, error) {
+	return (&postRelation{}).Last(ctx, db)
+}
+
+func (_ PostsQuerying) Limit(limit int64) PostRelation {
+	return (&postRelation{}).Limit(limit)
+}
+
+func (_ PostsQuerying) New() *Post {
+	return (&postRelation{}).New()
+}
+
+func (_ PostsQuerying) Not(value interface{}, args ...interface{}) PostRelation {
+	return (&postRelation{}).Not(value, args...)
+}
+
+func (_ PostsQuerying) Offset(offset int64) PostRelation {
+	return (&postRelation{}).Offset(offset)
+}
+
+func (_ PostsQuerying) Or(other PostRelation) PostRelation {
+	return (&postRelation{}).Or(other)
+}
+
+func (_ PostsQuerying) Order(query string, args ...string) PostRelation {
+	return (&postRelation{}).Order(query, args...)
+}
+
+func (_ PostsQuerying) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostPage, error) {
+	return (&postRelation{}).Paginate(ctx, db, cursor, pageSize)
+}
+
+func (_ PostsQuerying) Preload(associations ...string) PostRelation {
+	return (&postRelation{}).Preload(associations...)
+}
+
+func (_ PostsQuerying) Select(fields ...string) PostRelation {
+	return (&postRelation{}).Select(fields...)
+}
+
+func (_ PostsQuerying) Take(ctx context.Context, db DB) (*Post, error) {
+	return (&postRelation{}).Take(ctx, db)
+}
+
+func (_ PostsQuerying) Where(value interface{}, args ...interface{}) PostRelation {
+	return (&postRelation{}).Where(value, args...)
+}
+
+func (_ PostsQuerying) WhereBetween(field string, low, high interface{}) PostRelation {
+	return (&postRelation{}).WhereBetween(field, low, high)
+}
+
+func (_ PostsQuerying) WhereEq(field string, value interface{}) PostRelation {
+	return (&postRelation{}).WhereEq(field, value)
+}
+
+func (_ PostsQuerying) WhereIn(field string, values ...interface{}) PostRelation {
+	return (&postRelation{}).WhereIn(field, values...)
+}
+
+func (_ PostsQuerying) WhereLike(field, pattern string) PostRelation {
+	return (&postRelation{}).WhereLike(field, pattern)
+}
+
+func (_ PostsQuerying) WhereNot(field string, value interface{}) PostRelation {
+	return (&postRelation{}).WhereNot(field, value)
+}
+
+func (_ PostsQuerying) whereExpr() rel.Expr {
+	return (&postRelation{}).whereExpr()
+}
+
+func (_ PostsQuerying) joinExprs() []rel.Expr {
+	return (&postRelation{}).joinExprs()
+}
+
+// FindBySQL returns all the Posts selected by the given query
+func (q PostsQuerying) FindBySQL(ctx context.Context, db DB, query string, args ...interface{}) ([]*Post, error) {
+	var posts []*Post
+	err := q.EachBySQL(ctx, db, query, args, func(o *Post) error {
+		posts = append(posts, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// EachBySQL calls fn for each of the Posts selected by the given
+// query, as they're read from the database
+func (_ PostsQuerying) EachBySQL(ctx context.Context, db DB, query string, args []interface{}, fn func(*Post) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	row := &Post{}
+	row.persisted = true
+	fields, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	ptrs, err := row.pointersForFields(fields)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+
+		o := &Post{}
+		*o = *row
+		o.saved()
+
+		if err := fn(o); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// InsertAll inserts the records with as few statements as the limit on bind
+// parameters allows. Unlike Save, it doesn't validate the records or call
+// hooks. The IDs the database assigns are filled in, which not every
+// dialect can do. When a statement fails, the rows of the ones before it
+// stay inserted; use a Transaction to insert all or nothing.
+func (_ PostsQuerying) InsertAll(ctx context.Context, db DB, records []*Post) error {
+	now := Now()
+	for _, o := range records {
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = now
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = now
+		}
+	}
+
+	// The IDs can only be worked out for statements that leave them all to the database
+	var withID, withoutID []*Post
+	for _, o := range records {
+		if o.ID != 0 {
+			withID = append(withID, o)
+		} else {
+			withoutID = append(withoutID, o)
+		}
+	}
+	if _, ok := Dialect.FirstInsertID(0, 0); len(withoutID) > 0 && !ok && !Dialect.Returning() {
+		return errors.New("can't get the IDs of inserted rows with this dialect")
+	}
+
+	if err := insertPosts(ctx, db, withID, true, nil); err != nil {
+		return err
+	}
+	return insertPosts(ctx, db, withoutID, false, nil)
+}
+
+// UpsertAll inserts the records, or updates the rows that have the same
+// values for the conflict columns, which need a unique index. Those are the
+// primary key if none are given. Like InsertAll, it doesn't validate the
+// records or call hooks. The records are read back from the rows afterwards,
+// as existing rows keep their keys and creation timestamps.
+func (_ PostsQuerying) UpsertAll(ctx context.Context, db DB, records []*Post, conflictColumns ...string) error {
+	primaryKey := []string{"id"}
+	if len(conflictColumns) == 0 {
+		conflictColumns = primaryKey
+	}
+	for _, column := range conflictColumns {
+		if (&Post{}).fieldPointerForColumn(column) == nil {
+			return errors.Errorf("unknown column %q", column)
+		}
+	}
+
+	now := Now()
+	for _, o := range records {
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = now
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = now
+		}
+		o.UpdatedAt = now
+	}
+
+	onConflict := &rel.OnConflict{Columns: conflictColumns}
+	for _, column := range []string{"user_id", "category_id", "body", "updated_at"} {
+		if !containsColumn(conflictColumns, column) {
+			onConflict.Update = append(onConflict.Update, rel.Assignment{
+				Field: rel.Field{column},
+				Value: rel.Excluded{column},
+			})
+		}
+	}
+
+	var withID, withoutID []*Post
+	for _, o := range records {
+		if o.ID != 0 {
+			withID = append(withID, o)
+		} else {
+			withoutID = append(withoutID, o)
+		}
+	}
+	if len(withoutID) > 0 && sameColumns(conflictColumns, primaryKey) {
+		return errors.New("can't upsert on the ID of records without one")
+	}
+	if err := insertPosts(ctx, db, withID, true, onConflict); err != nil {
+		return err
+	}
+	return insertPosts(ctx, db, withoutID, false, onConflict)
+}
+
+// readPostsRows copies the rows with the same values for the
+// columns into the records, as upserted rows can keep values of the existing
+// ones. The rows an upsert returned are used first, the others are selected.
+func readPostsRows(ctx context.Context, db DB, records, returned []*Post, columns []string) error {
+	keyOf := func(o *Post) string {
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			values[i] = o.valueForColumn(column)
+		}
+		key, err := json.Marshal(values)
+		if err != nil {
+			panic(err)
+		}
+		return string(key)
+	}
+
+	byKey := make(map[string]*Post, len(records))
+	for _, row := range returned {
+		byKey[keyOf(row)] = row
+	}
+	var missing []*Post
+	for _, o := range records {
+		if _, ok := byKey[keyOf(o)]; !ok {
+			missing = append(missing, o)
+		}
+	}
+
+	size := Dialect.MaxParams() / len(columns)
+	for len(missing) > 0 {
+		chunk := missing
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		missing = missing[len(chunk):]
+
+		matches := make(rel.Or, len(chunk))
+		for i, o := range chunk {
+			match := make(rel.And, len(columns))
+			for j, column := range columns {
+				match[j] = rel.Equality{
+					Field: rel.Field{"posts." + column},
+					Value: rel.BindParam{Value: o.valueForColumn(column)},
+				}
+			}
+			matches[i] = match
+		}
+		rows, err := (&postRelation{whereClause: []rel.Expr{matches}}).All(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			byKey[keyOf(row)] = row
+		}
+	}
+
+	for _, o := range records {
+		row, ok := byKey[keyOf(o)]
+		if !ok {
+			return errors.New("upserted row not found")
+		}
+		o.ID = row.ID
+		o.UserID = row.UserID
+		o.CategoryID = row.CategoryID
+		o.Body = row.Body
+		o.CreatedAt = row.CreatedAt
+		o.UpdatedAt = row.UpdatedAt
+		o.persisted = true
+		o.saved()
+	}
+	return nil
+}
+
+// insertPosts inserts the records in statements of as many rows as fit,
+// handling conflicts with onConflict if it's set. The IDs are inserted too if
+// withID is true, and filled in otherwise. Upserted records are read back
+// from their rows.
+func insertPosts(ctx context.Context, db DB, records []*Post, withID bool, onConflict *rel.OnConflict) error {
+	var columns []string
+	if withID {
+		columns = append(columns, "id")
+	}
+	columns = append(columns, "user_id")
+	columns = append(columns, "category_id")
+	columns = append(columns, "body")
+	columns = append(columns, "created_at")
+	columns = append(columns, "updated_at")
+	size := Dialect.MaxParams() / len(columns)
+
+	for len(records) > 0 {
+		chunk := records
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		records = records[len(chunk):]
+
+		stmt := &rel.InsertStatement{
+			Dialect:    Dialect,
+			Table:      "posts",
+			Columns:    columns,
+			OnConflict: onConflict,
+		}
+		for _, o := range chunk {
+			row := make([]rel.Expr, 0, len(columns))
+			if withID {
+				row = append(row, &rel.BindParam{Value: o.ID})
+			}
+			row = append(row, &rel.BindParam{Value: o.UserID})
+			row = append(row, &rel.BindParam{Value: o.CategoryID})
+			row = append(row, &rel.BindParam{Value: o.Body})
+			row = append(row, &rel.BindParam{Value: o.CreatedAt})
+			row = append(row, &rel.BindParam{Value: o.UpdatedAt})
+			stmt.Rows = append(stmt.Rows, row)
+		}
+
+		// Existing rows keep some of their values, so upserted rows are read back
+		if onConflict != nil {
+			var returned []*Post
+			if Dialect.Returning() {
+				stmt.Returning = postColumns
+				query, values := stmt.Build()
+				var err error
+				if returned, err = Posts().FindBySQL(ctx, db, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				query, values := stmt.Build()
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			}
+			if err := readPostsRows(ctx, db, chunk, returned, onConflict.Columns); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Without the first ID, the database returns the IDs of the rows in the
+		// order they're inserted
+		_, counted := Dialect.FirstInsertID(0, 0)
+		returningIDs := !withID && !counted
+		if returningIDs {
+			stmt.Returning = []string{"id"}
+		}
+
+		query, values := stmt.Build()
+		if returningIDs {
+			rows, err := db.QueryContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			if err := scanPostsIDs(rows, chunk); err != nil {
+				return err
+			}
+		} else {
+			res, err := db.ExecContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			if !withID {
+				last, err := res.LastInsertId()
+				if err != nil {
+					return err
+				}
+				first, _ := Dialect.FirstInsertID(last, len(chunk))
+				for i, o := range chunk {
+					o.ID = first + int64(i)
+				}
+			}
+		}
+
+		for _, o := range chunk {
+			o.persisted = true
+			o.saved()
+		}
+	}
+
+	return nil
+}
+
+// scanPostsIDs reads the IDs returned by an insert into the records
+func scanPostsIDs(rows *sql.Rows, records []*Post) error {
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		if n < len(records) {
+			if err := rows.Scan(&records[n].ID); err != nil {
+				return err
+			}
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if n != len(records) {
+		return errors.Errorf("inserted %d rows, but got %d IDs", len(records), n)
+	}
+	return nil
+}
+
+// CountBySQL executes the given query, giving a count
+func (_ PostsQuerying) CountBySQL(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	var count int64
+	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+type postRelation struct {
+	fields      []string
+	joins       []rel.Expr
+	preloads    []string
+	whereClause []rel.Expr
+	orderValues []rel.Expr
+	limit       int64
+	offset      int64
+}
+
+func (q *postRelation) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	clauses := []rel.Expr{rel.Literal{Text: query, Params: args}}
+	now := Now()
+	clauses = append(clauses, rel.Assignment{
+		Field: rel.Field{"updated_at"},
+		Value: rel.BindParam{Value: now},
+	})
+
+	stmt := &rel.UpdateStatement{
+		Dialect: Dialect,
+		Table:   "posts",
+		Wheres:  q.keyedWheres(),
+		Values:  clauses,
+	}
+
+	query, values := stmt.Build()
+	res, err := db.ExecContext(ctx, query, values...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (q *postRelation) ToSQL() (query string, args []interface{}) {
+	return q.selectStatement().Build()
+}
+
+func (q *postRelation) selectStatement() *rel.SelectStatement {
+	return &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: q.columns(),
+		Table:   "posts",
+		Joins:   q.joins,
+		Wheres:  q.whereClause,
+		Orders:  q.orderValues,
+		Limit:   q.limit,
+		Offset:  q.offset,
+	}
+}
+
+// keyedWheres are the conditions for updating or deleting the relation's
+// records. Those statements can't join, so with joins the records are
+// matched by their primary key instead.
+func (q *postRelation) keyedWheres() []rel.Expr {
+	if len(q.joins) == 0 {
+		return q.whereClause
+	}
+
+	keys := rel.ExprList{
+		rel.Field{"posts.id"},
+	}
+	// MySQL can't select from the table that's changed in a subquery, unless
+	// it's wrapped in a derived table
+	return []rel.Expr{rel.In{
+		Left: rel.Grouping{Expr: keys},
+		Right: rel.ExprList{&rel.SelectStatement{
+			Columns: []rel.Expr{
+				rel.Field{"keys.id"},
+			},
+			Table: "keys",
+			From: &rel.SelectStatement{
+				Columns: keys,
+				Table:   "posts",
+				Joins:   q.joins,
+				Wheres:  q.whereClause,
+			},
+		}},
+	}}
+}
+
+func (q *postRelation) Count(ctx context.Context, db DB) (int64, error) {
+	var count int64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "COUNT"}, &count)
+	return count, err
+}
+
+func (q *postRelation) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{rel.Field{column}, rel.Aggregate{Func: "COUNT"}}
+	s.Groups = []rel.Expr{rel.Field{column}}
+	s.Orders = nil
+
+	query, args := s.Build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[interface{}]int64)
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		// Text can come back as bytes, which can't be a map key
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		counts[value] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (q *postRelation) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	var sum sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Float64, err
+}
+
+func (q *postRelation) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	var sum sql.NullInt64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Int64, err
+}
+
+func (q *postRelation) Average(ctx context.Context, db DB, column string) (float64, error) {
+	var avg sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "AVG", Expr: rel.Field{column}}, &avg)
+	return avg.Float64, err
+}
+
+func (q *postRelation) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MIN", Expr: rel.Field{column}}, dest)
+}
+
+func (q *postRelation) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MAX", Expr: rel.Field{column}}, dest)
+}
+
+// aggregate selects a single aggregate over the relation's records into dest
+func (q *postRelation) aggregate(ctx context.Context, db DB, aggregate rel.Aggregate, dest interface{}) error {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{aggregate}
+	// Ordering only makes sense for the records themselves
+	s.Orders = nil
+
+	query, args := s.Build()
+	// Aggregates lose the column's type, so SQLite returns times as text
+	switch dest.(type) {
+	case *time.Time, *sql.NullTime, **time.Time:
+		dest = timeScanner{dest: dest}
+	}
+	return db.QueryRowContext(ctx, query, args...).Scan(dest)
+}
+
+func (q *postRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	s := rel.DeleteStatement{
+		Dialect: Dialect,
+		Table:   "posts",
+		Wheres:  q.keyedWheres(),
+	}
+
+	query, args := s.Build()
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (q *postRelation) Where(value interface{}, args ...interface{}) PostRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, clauses...)
+
+	return q
+}
+
+func (q *postRelation) WhereEq(field string, value interface{}) PostRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Equality{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *postRelation) WhereBetween(field string, low, high interface{}) PostRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Between{
+		Field: rel.Field{field},
+		Low:   rel.BindParam{low},
+		High:  rel.BindParam{high},
+	})
+
+	return q
+}
+
+func (q *postRelation) WhereIn(field string, values ...interface{}) PostRelation {
+	q = q.clone()
+	params := make(rel.ExprList, len(values))
+	for i, value := range values {
+		params[i] = rel.BindParam{value}
+	}
+	q.whereClause = append(q.whereClause, rel.In{
+		Left:  rel.Field{field},
+		Right: params,
+	})
+
+	return q
+}
+
+func (q *postRelation) WhereLike(field, pattern string) PostRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Like{
+		Field:   rel.Field{field},
+		Pattern: rel.BindParam{pattern},
+	})
+
+	return q
+}
+
+func (q *postRelation) WhereNot(field string, value interface{}) PostRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.NotEqual{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *postRelation) Not(value interface{}, args ...interface{}) PostRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, rel.Not{Expr: rel.And(clauses)})
+
+	return q
+}
+
+func (q *postRelation) Or(other PostRelation) PostRelation {
+	if !sameJoins(q.joins, other.joinExprs()) {
+		panic("relation passed to Or must have the same joins")
+	}
+	q = q.clone()
+	q.whereClause = []rel.Expr{rel.Or{q.whereExpr(), other.whereExpr()}}
+
+	return q
+}
+
+// clone copies the relation, so the methods building on it leave it as it
+// is. The slices are capped, so appending to them copies them as well.
+func (q *postRelation) clone() *postRelation {
+	c := *q
+	c.fields = c.fields[:len(c.fields):len(c.fields)]
+	c.joins = c.joins[:len(c.joins):len(c.joins)]
+	c.preloads = c.preloads[:len(c.preloads):len(c.preloads)]
+	c.whereClause = c.whereClause[:len(c.whereClause):len(c.whereClause)]
+	c.orderValues = c.orderValues[:len(c.orderValues):len(c.orderValues)]
+	return &c
+}
 
-	q.whereClause = append(q.whereClause, clauses...)
+// whereExpr combines the conditions of the relation into a single expression
+func (q *postRelation) whereExpr() rel.Expr {
+	return rel.And(q.whereClause)
+}
 
-	return q
+func (q *postRelation) joinExprs() []rel.Expr {
+	return q.joins
 }
 
-func (q *userRelation) WhereEq(field string, value interface{}) UserRelation {
-	q.whereClause = append(q.whereClause, rel.Equality{
-		Field: rel.Field{field},
-		Value: rel.BindParam{value},
-	})
+func (q *postRelation) Joins(associations ...string) PostRelation {
+	q = q.clone()
+	for _, association := range associations {
+		switch association {
+		case "post_tags":
+			q.joins = append(q.joins, rel.InnerJoin{
+				Table: "post_tags",
+				On: rel.Equality{
+					Field: rel.Field{"post_tags.post_id"},
+					Value: rel.Field{"posts.id"},
+				},
+			})
+		case "users":
+			q.joins = append(q.joins, rel.InnerJoin{
+				Table: "users",
+				On: rel.Equality{
+					Field: rel.Field{"users.id"},
+					Value: rel.Field{"posts.user_id"},
+				},
+			})
+		case "categories":
+			q.joins = append(q.joins, rel.InnerJoin{
+				Table: "categories",
+				On: rel.Equality{
+					Field: rel.Field{"categories.slug"},
+					Value: rel.Field{"posts.category_id"},
+				},
+			})
+		default:
+			panic(fmt.Sprintf("unknown association %q", association))
+		}
+	}
 
 	return q
 }
 
-func (q *userRelation) Limit(limit int64) UserRelation {
+func (q *postRelation) Limit(limit int64) PostRelation {
+	q = q.clone()
 	q.limit = limit
 	return q
 }
 
-func (q *userRelation) New() *User {
-	o := &User{}
+func (q *postRelation) New() *Post {
+	o := &Post{}
 	for _, w := range q.whereClause {
 		if eq, ok := w.(rel.Equality); ok {
-			if bind, ok := eq.Value.(rel.BindParam); ok {
-				o.assignField(eq.Field.Name, bind.Value)
+			name := strings.TrimPrefix(eq.Field.Name, "posts.")
+			if eq.Value == nil {
+				o.assignField(name, nil)
+			} else if bind, ok := eq.Value.(rel.BindParam); ok {
+				o.assignField(name, bind.Value)
 			}
 		}
 	}
@@ -582,34 +5903,136 @@ func (q *userRelation) New() *User {
 	return o
 }
 
-func (q *userRelation) Select(fields ...string) UserRelation {
+func (q *postRelation) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostPage, error) {
+	if pageSize <= 0 {
+		return nil, errors.Errorf("invalid page size %d", pageSize)
+	}
+	if q.limit != 0 || q.offset != 0 {
+		return nil, errors.New("Paginate can't be used with a limit or offset")
+	}
+
+	keys, err := parseOrder("posts", q.orderValues)
+	if err != nil {
+		return nil, err
+	}
+	edge := &Post{}
+	for _, key := range keys {
+		if !edge.sortable(key.column) {
+			return nil, errors.Errorf("can't paginate by %q", key.column)
+		}
+	}
+	keys = appendSortKey(keys, "id")
+
+	page := q.clone()
+	page.limit = int64(pageSize) + 1
+	// The cursors need the values of the keys
+	if page.fields != nil {
+		for _, key := range keys {
+			page.fields = append(page.fields, "posts."+key.column)
+		}
+	}
+	var position cursorPosition
+	if cursor != "" {
+		if position, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			value, ok := position.Values[key.column]
+			if !ok || json.Unmarshal(value, edge.fieldPointerForColumn(key.column)) != nil {
+				return nil, errors.Errorf("invalid cursor %q", cursor)
+			}
+		}
+		page.whereClause = append(page.whereClause, seek("posts", keys, edge.valueForColumn, position.Before))
+	}
+	page.orderValues = nil
+	for _, key := range keys {
+		field := rel.Field{Name: "posts." + key.column}
+		if key.descending != position.Before {
+			page.orderValues = append(page.orderValues, rel.Descending{Expr: field})
+		} else {
+			page.orderValues = append(page.orderValues, rel.Ascending{Expr: field})
+		}
+	}
+
+	records, err := page.All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	more := len(records) > pageSize
+	if more {
+		records = records[:pageSize]
+	}
+	if position.Before {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	result := &PostPage{Records: records}
+	if len(records) == 0 {
+		return result, nil
+	}
+	if more && !position.Before || cursor != "" && position.Before {
+		result.Next = records[len(records)-1].cursor(keys, false)
+	}
+	if more && position.Before || cursor != "" && !position.Before {
+		result.Previous = records[0].cursor(keys, true)
+	}
+	return result, nil
+}
+
+func (q *postRelation) Preload(associations ...string) PostRelation {
+	q = q.clone()
+	q.preloads = append(q.preloads, associations...)
+	return q
+}
+
+func (q *postRelation) Select(fields ...string) PostRelation {
+	q = q.clone()
 	q.fields = append(q.fields, fields...)
 	return q
 }
 
-func (q *userRelation) Offset(offset int64) UserRelation {
+func (q *postRelation) Offset(offset int64) PostRelation {
+	q = q.clone()
 	q.offset = offset
 	return q
 }
 
-func (q *userRelation) columnFields() []string {
+func (q *postRelation) columns() []rel.Expr {
 	if q.fields == nil {
-		return []string{
-			"id",
-			"first_name",
-			"last_name",
+		return []rel.Expr{
+			rel.Field{"posts.id"},
+			rel.Field{"posts.user_id"},
+			rel.Field{"posts.category_id"},
+			rel.Field{"posts.body"},
+			rel.Field{"posts.created_at"},
+			rel.Field{"posts.updated_at"},
 		}
-	} else {
-		return q.fields
 	}
+
+	columns := make([]rel.Expr, 0, len(q.fields))
+	for _, field := range q.fields {
+		columns = append(columns, &rel.Literal{Text: field})
+	}
+	return columns
 }
 
-func (q *userRelation) All(ctx context.Context, db DB) ([]*User, error) {
+func (q *postRelation) All(ctx context.Context, db DB) ([]*Post, error) {
 	query, args := q.ToSQL()
-	return Users().FindBySQL(ctx, db, query, args...)
+	records, err := Posts().FindBySQL(ctx, db, query, args...)
+	if err != nil || len(q.preloads) == 0 {
+		return records, err
+	}
+
+	if err := preloadPosts(ctx, db, records, q.preloads); err != nil {
+		return nil, err
+	}
+	return records, nil
 }
 
-func (q *userRelation) Take(ctx context.Context, db DB) (*User, error) {
+func (q *postRelation) Take(ctx context.Context, db DB) (*Post, error) {
+	q = q.clone()
 	q.limit = 1
 	os, err := q.All(ctx, db)
 	if err != nil {
@@ -623,23 +6046,72 @@ func (q *userRelation) Take(ctx context.Context, db DB) (*User, error) {
 	return os[0], nil
 }
 
-func (q *userRelation) Find(ctx context.Context, db DB, id int64) (*User, error) {
-	return q.FindBy(ctx, db, "id = ?", id)
+func (q *postRelation) Each(ctx context.Context, db DB, fn func(*Post) error) error {
+	if len(q.preloads) > 0 {
+		return errors.New("Each can't preload associations, use FindInBatches")
+	}
+	query, args := q.ToSQL()
+	return Posts().EachBySQL(ctx, db, query, args, fn)
 }
 
-func (q *userRelation) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*User, error) {
+func (q *postRelation) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*Post) error) error {
+	if batchSize <= 0 {
+		return errors.Errorf("invalid batch size %d", batchSize)
+	}
+	if len(q.orderValues) > 0 || q.limit != 0 || q.offset != 0 {
+		return errors.New("FindInBatches can't be used with an order, limit or offset")
+	}
+
+	batch := q.clone()
+	batch.limit = int64(batchSize)
+	// The next batch starts after the key of the last record, so it has to be
+	// selected
+	if batch.fields != nil {
+		batch.fields = append(batch.fields, "posts.id")
+	}
+	batch.orderValues = append(batch.orderValues, rel.Ascending{Expr: rel.Field{"posts.id"}})
+	for {
+		records, err := batch.All(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+
+		batch = batch.clone()
+		batch.whereClause = append(q.whereClause[:len(q.whereClause):len(q.whereClause)], records[len(records)-1].afterKey())
+	}
+}
+
+func (q *postRelation) Find(ctx context.Context, db DB, id int64) (*Post, error) {
+	return q.WhereEq("posts.id", id).Take(ctx, db)
+}
+
+func (q *postRelation) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error) {
 	return q.Where(query, args...).Take(ctx, db)
 }
 
-func (q *userRelation) First(ctx context.Context, db DB) (*User, error) {
-	return q.Order("id ASC").Take(ctx, db)
+func (q *postRelation) First(ctx context.Context, db DB) (*Post, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Ascending{Expr: rel.Field{"posts.id"}})
+	return q.Take(ctx, db)
 }
 
-func (q *userRelation) Last(ctx context.Context, db DB) (*User, error) {
-	return q.Order("id DESC").Take(ctx, db)
+func (q *postRelation) Last(ctx context.Context, db DB) (*Post, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Descending{Expr: rel.Field{"posts.id"}})
+	return q.Take(ctx, db)
 }
 
-func (q *userRelation) Order(query string, args ...string) UserRelation {
+func (q *postRelation) Order(query string, args ...string) PostRelation {
+	q = q.clone()
 	q.orderValues = append(q.orderValues, &rel.Literal{Text: query})
 
 	for i := 0; i < len(args); i++ {
@@ -649,168 +6121,532 @@ func (q *userRelation) Order(query string, args ...string) UserRelation {
 	return q
 }
 
-type Post struct {
-	// ID ...
-	ID int64
+// preloadPosts fills the association caches of the records for the
+// given association paths
+func preloadPosts(ctx context.Context, db DB, records []*Post, paths []string) error {
+	if len(records) == 0 {
+		return nil
+	}
 
-	// UserID ...
-	UserID int64
+	// The nested paths are preloaded along with their first association
+	var names []string
+	nested := make(map[string][]string)
+	for _, path := range paths {
+		name, rest := path, ""
+		if i := strings.Index(path, "."); i >= 0 {
+			name, rest = path[:i], path[i+1:]
+		}
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = []string{}
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
 
-	// Body ...
-	Body string
+	for _, name := range names {
+		switch name {
+		case "PostTags":
+			var keys []interface{}
+			seen := make(map[int64]bool, len(records))
+			for _, o := range records {
+				if !seen[o.ID] {
+					seen[o.ID] = true
+					keys = append(keys, o.ID)
+				}
+			}
+
+			byKey := make(map[int64][]*PostTag)
+			for _, chunk := range keyChunks(keys) {
+				children, err := PostTags().WhereIn("post_tags.post_id", chunk...).Preload(nested[name]...).All(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, c := range children {
+					byKey[c.PostID] = append(byKey[c.PostID], c)
+				}
+			}
+			for _, o := range records {
+				o.associations.PostTags.records = byKey[o.ID]
+				o.associations.PostTags.loaded = true
+			}
+		case "User":
+			var keys []interface{}
+			seen := make(map[int64]bool)
+			for _, o := range records {
+				if key := o.UserID; !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+
+			byKey := make(map[int64]*User, len(keys))
+			for _, chunk := range keyChunks(keys) {
+				parents, err := Users().WhereIn("users.id", chunk...).Preload(nested[name]...).All(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, p := range parents {
+					byKey[p.ID] = p
+				}
+			}
+			for _, o := range records {
+				o.associations.Users.record = byKey[o.UserID]
+				o.associations.Users.loaded = true
+			}
+		case "Category":
+			var keys []interface{}
+			seen := make(map[string]bool)
+			for _, o := range records {
+				if !o.CategoryID.Valid {
+					continue
+				}
+				if key := o.CategoryID.String; !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+
+			byKey := make(map[string]*Category, len(keys))
+			for _, chunk := range keyChunks(keys) {
+				parents, err := Categories().WhereIn("categories.slug", chunk...).Preload(nested[name]...).All(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, p := range parents {
+					byKey[p.Slug] = p
+				}
+			}
+			for _, o := range records {
+				if !o.CategoryID.Valid {
+					o.associations.Categories.record = nil
+				} else {
+					o.associations.Categories.record = byKey[o.CategoryID.String]
+				}
+				o.associations.Categories.loaded = true
+			}
+		default:
+			return errors.Errorf("posts: unknown association %q", name)
+		}
+	}
+
+	return nil
+}
+
+type PostTag struct {
+	// PostID ...
+	PostID int64
+
+	// Tag ...
+	Tag string
 
 	// If true, then this record exists in the DB
 	persisted bool
 	deleted   bool
 
 	old struct {
-		// ID ...
-		ID int64
-
-		// UserID ...
-		UserID int64
+		// PostID ...
+		PostID int64
 
-		// Body ...
-		Body string
+		// Tag ...
+		Tag string
 	}
 
 	associations struct {
-		Users struct {
+		Posts struct {
 			loaded bool
-			record *User
+			record *Post
 		}
 	}
 }
 
-func (o *Post) User(ctx context.Context, db DB) (*User, error) {
-	if o.associations.Users.loaded {
-		return o.associations.Users.record, nil
+func (o *PostTag) Post(ctx context.Context, db DB) (*Post, error) {
+	if o.associations.Posts.loaded {
+		return o.associations.Posts.record, nil
 	}
 
-	record, err := Users().Find(ctx, db, o.UserID)
+	record, err := Posts().Find(ctx, db, o.PostID)
 	if err != nil {
 		return nil, err
 	}
 
-	o.associations.Users.record = record
-	o.associations.Users.loaded = true
+	o.associations.Posts.record = record
+	o.associations.Posts.loaded = true
 
 	return record, nil
 }
 
-func (o *Post) Save(ctx context.Context, db DB) error {
+func (o *PostTag) Save(ctx context.Context, db DB) error {
 	if o.deleted {
 		return fmt.Errorf("record deleted")
 	}
 
+	if err := o.Validate(ctx, db); err != nil {
+		return err
+	}
+
+	if hook, ok := interface{}(o).(BeforeSaveHook); ok {
+		if err := hook.BeforeSave(ctx, db); err != nil {
+			return err
+		}
+	}
+
 	if o.persisted {
+		if hook, ok := interface{}(o).(BeforeUpdateHook); ok {
+			if err := hook.BeforeUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+
 		stmt := &rel.UpdateStatement{
-			Table: "posts",
-			Wheres: []rel.Expr{
-				rel.Assignment{
-					Field: rel.Field{"id"},
-					Value: rel.BindParam{Value: o.old.ID},
+			Dialect: Dialect,
+			Table:   "post_tags",
+			Wheres:  o.keyWheres(),
+		}
+
+		if o.PostID != o.old.PostID {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"post_id"},
+				Value: &rel.BindParam{
+					Value: o.PostID,
 				},
-			},
+			})
+		}
+
+		if o.Tag != o.old.Tag {
+			stmt.Values = append(stmt.Values, rel.Assignment{
+				Field: rel.Field{"tag"},
+				Value: &rel.BindParam{
+					Value: o.Tag,
+				},
+			})
+		}
+
+		// There's nothing to write when no field changed
+		if len(stmt.Values) > 0 {
+			if Dialect.Returning() {
+				stmt.Returning = post_tagColumns
+			}
+			query, values := stmt.Build()
+			if Dialect.Returning() {
+				if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+				o.saved()
+				if err := o.reload(ctx, db); err != nil {
+					return err
+				}
+			}
+		}
+		if hook, ok := interface{}(o).(AfterUpdateHook); ok {
+			if err := hook.AfterUpdate(ctx, db); err != nil {
+				return err
+			}
+		}
+	} else {
+		if hook, ok := interface{}(o).(BeforeCreateHook); ok {
+			if err := hook.BeforeCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+
+		stmt := &rel.InsertStatement{
+			Dialect: Dialect,
+			Table:   "post_tags",
+		}
+
+		stmt.Columns = append(stmt.Columns, "post_id")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.PostID,
+		})
+		stmt.Columns = append(stmt.Columns, "tag")
+		stmt.Values = append(stmt.Values, &rel.BindParam{
+			Value: o.Tag,
+		})
+
+		if Dialect.Returning() {
+			stmt.Returning = post_tagColumns
+		}
+		query, values := stmt.Build()
+		if Dialect.Returning() {
+			if err := o.scan(db.QueryRowContext(ctx, query, values...)); err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+		} else {
+			_, err := db.ExecContext(ctx, query, values...)
+			if err != nil {
+				return errors.Wrapf(err, "executing %q", query)
+			}
+			o.persisted = true
+
+			o.saved()
+			if err := o.reload(ctx, db); err != nil {
+				return err
+			}
+		}
+		if hook, ok := interface{}(o).(AfterCreateHook); ok {
+			if err := hook.AfterCreate(ctx, db); err != nil {
+				return err
+			}
+		}
+	}
+
+	if hook, ok := interface{}(o).(AfterSaveHook); ok {
+		if err := hook.AfterSave(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upsert inserts the record, or updates the row that has the same values for
+// the conflict columns, see UpsertAll
+func (o *PostTag) Upsert(ctx context.Context, db DB, conflictColumns ...string) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	return PostTags().UpsertAll(ctx, db, []*PostTag{o}, conflictColumns...)
+}
+
+// Validate checks the values against the validations in the schema. It
+// returns ValidationErrors when they don't pass.
+func (o *PostTag) Validate(ctx context.Context, db DB) error {
+	errs := ValidationErrors{}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// IsPersisted is true once the record has been loaded or saved
+func (o *PostTag) IsPersisted() bool {
+	return o.persisted
+}
+
+// IsDeleted is true once the record has been deleted
+func (o *PostTag) IsDeleted() bool {
+	return o.deleted
+}
+
+// IsChanged is true if any of the fields differ from the row the record was
+// loaded or last saved as. For new records, that's any field that isn't zero.
+func (o *PostTag) IsChanged() bool {
+	return o.PostID != o.old.PostID ||
+		o.Tag != o.old.Tag
+}
+
+// ChangedColumns returns the columns of the fields that changed, in the order
+// of the table
+func (o *PostTag) ChangedColumns() []string {
+	var columns []string
+	if o.PostID != o.old.PostID {
+		columns = append(columns, "post_id")
+	}
+	if o.Tag != o.old.Tag {
+		columns = append(columns, "tag")
+	}
+	return columns
+}
+
+// Changes returns the old and new value of the changed fields by column
+func (o *PostTag) Changes() map[string][2]interface{} {
+	changes := map[string][2]interface{}{}
+	if o.PostID != o.old.PostID {
+		changes["post_id"] = [2]interface{}{o.old.PostID, o.PostID}
+	}
+	if o.Tag != o.old.Tag {
+		changes["tag"] = [2]interface{}{o.old.Tag, o.Tag}
+	}
+	return changes
+}
+
+// Restore reverts the fields to the row the record was loaded or last saved
+// as, undoing the changes
+func (o *PostTag) Restore() {
+	o.PostID = o.old.PostID
+	o.Tag = o.old.Tag
+}
+
+// Reload reads the record's row again and resets the association caches.
+// It returns ErrNotFound if the row no longer exists.
+func (o *PostTag) Reload(ctx context.Context, db DB) error {
+	if o.deleted {
+		return fmt.Errorf("record deleted")
+	}
+	if !o.persisted {
+		return fmt.Errorf("record not persisted")
+	}
+	if err := o.reload(ctx, db); err != nil {
+		return err
+	}
+	var reset PostTag
+	o.associations = reset.associations
+	return nil
+}
+
+// saved records that the fields match the row
+func (o *PostTag) saved() {
+	o.old.PostID = o.PostID
+	o.old.Tag = o.Tag
+}
+
+// post_tagColumns are the columns Save reads back after writing a row
+var post_tagColumns = []string{"post_id", "tag"}
+
+// scan reads the row's columns into the record, which then matches it
+func (o *PostTag) scan(row *sql.Row) error {
+	ptrs, err := o.pointersForFields(post_tagColumns)
+	if err != nil {
+		return err
+	}
+	if err := row.Scan(ptrs...); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
 		}
+		return err
+	}
+	o.saved()
+	return nil
+}
 
-		if o.ID != o.old.ID {
-			stmt.Values = append(stmt.Values, rel.Assignment{
-				Field: rel.Field{"id"},
-				Value: &rel.BindParam{
-					Value: o.ID,
-				},
-			})
-		}
+// reload selects the record's row again, to see the values the database set
+// when the dialect can't return them from the insert or update
+func (o *PostTag) reload(ctx context.Context, db DB) error {
+	stmt := &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: (&post_tagRelation{}).columns(),
+		Table:   "post_tags",
+		Wheres:  o.keyWheres(),
+	}
+	query, values := stmt.Build()
+	return o.scan(db.QueryRowContext(ctx, query, values...))
+}
 
-		if o.UserID != o.old.UserID {
-			stmt.Values = append(stmt.Values, rel.Assignment{
-				Field: rel.Field{"user_id"},
-				Value: &rel.BindParam{
-					Value: o.UserID,
-				},
-			})
+func (o *PostTag) Delete(ctx context.Context, db DB) error {
+	if hook, ok := interface{}(o).(BeforeDeleteHook); ok {
+		if err := hook.BeforeDelete(ctx, db); err != nil {
+			return err
 		}
+	}
 
-		if o.Body != o.old.Body {
-			stmt.Values = append(stmt.Values, rel.Assignment{
-				Field: rel.Field{"body"},
-				Value: &rel.BindParam{
-					Value: o.Body,
-				},
-			})
+	_, err := (&post_tagRelation{whereClause: o.keyWheres()}).DeleteAll(ctx, db)
+	if err != nil {
+		return err
+	}
+	o.deleted = true
+	if hook, ok := interface{}(o).(AfterDeleteHook); ok {
+		if err := hook.AfterDelete(ctx, db); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		query, values := stmt.Build()
-		_, err := db.ExecContext(ctx, query, values...)
-		if err != nil {
-			return errors.Wrapf(err, "executing %q", query)
-		}
-	} else {
-		stmt := &rel.InsertStatement{
-			Table: "posts",
+// keyWheres matches the record's row. Once persisted, that's by the key it
+// was loaded or last saved with, so changes to the key are saved too.
+func (o *PostTag) keyWheres() []rel.Expr {
+	if !o.persisted {
+		return []rel.Expr{
+			rel.Equality{
+				Field: rel.Field{"post_id"},
+				Value: rel.BindParam{Value: o.PostID},
+			},
+			rel.Equality{
+				Field: rel.Field{"tag"},
+				Value: rel.BindParam{Value: o.Tag},
+			},
 		}
+	}
 
-		if o.ID != 0 {
-			stmt.Columns = append(stmt.Columns, "id")
-			stmt.Values = append(stmt.Values, &rel.BindParam{
-				Value: o.ID,
-			})
-		}
-		stmt.Columns = append(stmt.Columns, "user_id")
-		stmt.Values = append(stmt.Values, &rel.BindParam{
-			Value: o.UserID,
-		})
-		stmt.Columns = append(stmt.Columns, "body")
-		stmt.Values = append(stmt.Values, &rel.BindParam{
-			Value: o.Body,
-		})
+	return []rel.Expr{
+		rel.Equality{
+			Field: rel.Field{"post_id"},
+			Value: rel.BindParam{Value: o.old.PostID},
+		},
+		rel.Equality{
+			Field: rel.Field{"tag"},
+			Value: rel.BindParam{Value: o.old.Tag},
+		},
+	}
+}
 
-		query, values := stmt.Build()
-		res, err := db.ExecContext(ctx, query, values...)
-		if err != nil {
-			return errors.Wrapf(err, "executing %q", query)
-		}
-		o.persisted = true
+// afterKey matches the rows ordered after the record by primary key
+func (o *PostTag) afterKey() rel.Expr {
+	return rel.Or{
+		rel.And{
+			rel.GreaterThan{
+				Field: rel.Field{"post_tags.post_id"},
+				Value: rel.BindParam{Value: o.PostID},
+			},
+		},
+		rel.And{
+			rel.Equality{
+				Field: rel.Field{"post_tags.post_id"},
+				Value: rel.BindParam{Value: o.PostID},
+			},
+			rel.GreaterThan{
+				Field: rel.Field{"post_tags.tag"},
+				Value: rel.BindParam{Value: o.Tag},
+			},
+		},
+	}
+}
 
-		if o.ID == 0 {
-			o.ID, err = res.LastInsertId()
-			if err != nil {
-				return err
-			}
+// cursor is the position of the record in the order of the keys
+func (o *PostTag) cursor(keys []sortKey, before bool) Cursor {
+	position := cursorPosition{Before: before, Values: make(map[string]json.RawMessage, len(keys))}
+	for _, key := range keys {
+		value, err := json.Marshal(o.valueForColumn(key.column))
+		if err != nil {
+			panic(err)
 		}
+		position.Values[key.column] = value
 	}
+	return position.encode()
+}
 
-	o.old.ID = o.ID
-	o.old.UserID = o.UserID
-	o.old.Body = o.Body
-
-	return nil
+func (o *PostTag) fieldPointerForColumn(column string) interface{} {
+	switch column {
+	case "post_id":
+		return &o.PostID
+	case "tag":
+		return &o.Tag
+	default:
+		return nil
+	}
 }
 
-func (o *Post) Delete(ctx context.Context, db DB) error {
-	_, err := Posts().WhereEq("id", o.ID).DeleteAll(ctx, db)
-	if err != nil {
-		return err
+// sortable is true for the columns Paginate can order by, which are the ones
+// that can't be NULL
+func (o *PostTag) sortable(column string) bool {
+	switch column {
+	case "post_id":
+		return true
+	case "tag":
+		return true
+	default:
+		return false
 	}
-	o.deleted = true
-	return err
 }
 
-func (o *Post) fieldPointerForColumn(column string) interface{} {
+func (o *PostTag) valueForColumn(column string) interface{} {
 	switch column {
-	case "id":
-		return &o.ID
-	case "user_id":
-		return &o.UserID
-	case "body":
-		return &o.Body
+	case "post_id":
+		return o.PostID
+	case "tag":
+		return o.Tag
 	default:
 		return nil
 	}
 }
 
-func (o *Post) pointersForFields(fields []string) ([]interface{}, error) {
+func (o *PostTag) pointersForFields(fields []string) ([]interface{}, error) {
 	pointers := make([]interface{}, len(fields))
 	for i, field := range fields {
 		ptr := o.fieldPointerForColumn(field)
@@ -824,18 +6660,24 @@ func (o *Post) pointersForFields(fields []string) ([]interface{}, error) {
 
 // assignField sets the field to the value.
 // It returns an error if the field doesn't exist or the value is the wrong type.
-func (o *Post) assignField(name string, value interface{}) error {
+func (o *PostTag) assignField(name string, value interface{}) error {
 	switch name {
-	case "id":
-		o.ID = value.(int64)
-
-		return nil
-	case "user_id":
-		o.UserID = value.(int64)
+	case "post_id":
+		switch v := value.(type) {
+		case int64:
+			o.PostID = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
 
 		return nil
-	case "body":
-		o.Body = value.(string)
+	case "tag":
+		switch v := value.(type) {
+		case string:
+			o.Tag = v
+		default:
+			return errors.Errorf("invalid value of type %T for field: %s", value, name)
+		}
 
 		return nil
 	default:
@@ -843,181 +6685,506 @@ func (o *Post) assignField(name string, value interface{}) error {
 	}
 }
 
-type PostRelation interface {
+// PostTagPage is a page of PostTags, see Paginate
+type PostTagPage struct {
+	Records []*PostTag
+
+	// Next is the cursor for the following page, empty if this is the last one
+	Next Cursor
+
+	// Previous is the cursor for the preceding page, empty if this is the first one
+	Previous Cursor
+}
+
+// PostTagRelation builds a query for PostTags. Relations are
+// immutable: each method returns a new relation, so a relation can be reused
+// as the base for other queries, and shared between goroutines.
+type PostTagRelation interface {
 	Relation
 
 	// All ...
-	All(ctx context.Context, db DB) ([]*Post, error)
+	All(ctx context.Context, db DB) ([]*PostTag, error)
+
+	// Each calls fn for each of the records as they're read from the
+	// database, without loading them all at once. The rows stay open while fn
+	// runs, so some drivers don't let fn query the same connection; use
+	// FindInBatches for that. Associations can't be preloaded.
+	Each(ctx context.Context, db DB, fn func(*PostTag) error) error
 
 	// Find ...
-	Find(ctx context.Context, db DB, id int64) (*Post, error)
+	Find(ctx context.Context, db DB, postID int64, tag string) (*PostTag, error)
 
 	// FindBy ...
-	FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error)
+	FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*PostTag, error)
+
+	// FindInBatches calls fn with batches of up to batchSize records, ordered
+	// by primary key. Each batch is selected after the last key of the
+	// previous one, so the relation can't have an order, limit or offset.
+	FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*PostTag) error) error
 
 	// First ...
-	First(ctx context.Context, db DB) (*Post, error)
+	First(ctx context.Context, db DB) (*PostTag, error)
+
+	// Joins adds an INNER JOIN for each of the associations, given by the
+	// associated table's name. The records are selected once for every match.
+	Joins(associations ...string) PostTagRelation
 
 	// Last ...
-	Last(ctx context.Context, db DB) (*Post, error)
+	Last(ctx context.Context, db DB) (*PostTag, error)
 
 	// Limit ...
-	Limit(limit int64) PostRelation
+	Limit(limit int64) PostTagRelation
 
-	// New creates a Post populated with the scope of the relation
-	New() *Post
+	// New creates a PostTag populated with the scope of the relation
+	New() *PostTag
+
+	// Not adds the negation of a condition, given like in Where
+	Not(value interface{}, args ...interface{}) PostTagRelation
 
 	// Offset ...
-	Offset(offset int64) PostRelation
+	Offset(offset int64) PostTagRelation
+
+	// Or selects the records matching the conditions of either relation. It
+	// panics when the relations don't have the same joins.
+	Or(other PostTagRelation) PostTagRelation
 
 	// Order ...
-	Order(query string, args ...string) PostRelation
+	Order(query string, args ...string) PostTagRelation
+
+	// Paginate selects the page of up to pageSize records at the cursor. The
+	// pages follow the order of the relation, which needs to be by columns, and
+	// then the primary key. Pages are found by the values at their edges
+	// instead of by offset, so they stay fast and don't skip or repeat records
+	// when others are added or removed. Nullable columns can't be ordered by,
+	// as NULLs don't compare.
+	Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostTagPage, error)
+
+	// Preload loads the named associations of the selected records with one
+	// query per association. Nested associations are separated by dots, like
+	// "Posts.PostTags".
+	Preload(associations ...string) PostTagRelation
 
 	// Select ...
-	Select(fields ...string) PostRelation
+	Select(fields ...string) PostTagRelation
 
 	// Take ...
-	Take(ctx context.Context, db DB) (*Post, error)
+	Take(ctx context.Context, db DB) (*PostTag, error)
 
 	// Where ...
-	Where(value interface{}, args ...interface{}) PostRelation
+	Where(value interface{}, args ...interface{}) PostTagRelation
+
+	// WhereBetween adds a condition that the field is between low and high, inclusive
+	WhereBetween(field string, low, high interface{}) PostTagRelation
 
 	// WhereEq ...
-	WhereEq(field string, value interface{}) PostRelation
+	WhereEq(field string, value interface{}) PostTagRelation
+
+	// WhereIn adds a condition that the field has one of the values
+	WhereIn(field string, values ...interface{}) PostTagRelation
+
+	// WhereLike adds a condition that the field matches the LIKE pattern, see rel.Like
+	WhereLike(field, pattern string) PostTagRelation
+
+	// WhereNot adds a condition that the field doesn't equal the value, or isn't NULL for nil
+	WhereNot(field string, value interface{}) PostTagRelation
+
+	whereExpr() rel.Expr
+	joinExprs() []rel.Expr
 }
 
-// PostsQuerying gives you access to Posts
-type PostsQuerying struct{}
+// PostTagsQuerying gives you access to PostTags
+type PostTagsQuerying struct{}
 
-// PostsQuerying gives you access to Posts
-func Posts() PostsQuerying {
-	return PostsQuerying{}
+// PostTagsQuerying gives you access to PostTags
+func PostTags() PostTagsQuerying {
+	return PostTagsQuerying{}
 }
 
-func (_ PostsQuerying) Count(ctx context.Context, db DB) (int64, error) {
-	return (&postRelation{}).Count(ctx, db)
+func (_ PostTagsQuerying) Average(ctx context.Context, db DB, column string) (float64, error) {
+	return (&post_tagRelation{}).Average(ctx, db, column)
 }
 
-func (_ PostsQuerying) DeleteAll(ctx context.Context, db DB) (int64, error) {
-	return (&postRelation{}).DeleteAll(ctx, db)
+func (_ PostTagsQuerying) Count(ctx context.Context, db DB) (int64, error) {
+	return (&post_tagRelation{}).Count(ctx, db)
 }
 
-func (_ PostsQuerying) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
-	return (&postRelation{}).UpdateAll(ctx, db, query, args...)
+func (_ PostTagsQuerying) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	return (&post_tagRelation{}).CountBy(ctx, db, column)
 }
 
-func (_ PostsQuerying) All(ctx context.Context, db DB) ([]*Post, error) {
-	return (&postRelation{}).All(ctx, db)
+func (_ PostTagsQuerying) DeleteAll(ctx context.Context, db DB) (int64, error) {
+	return (&post_tagRelation{}).DeleteAll(ctx, db)
 }
 
-func (_ PostsQuerying) Find(ctx context.Context, db DB, id int64) (*Post, error) {
-	return (&postRelation{}).Find(ctx, db, id)
+func (_ PostTagsQuerying) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&post_tagRelation{}).Maximum(ctx, db, column, dest)
 }
 
-func (_ PostsQuerying) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error) {
-	return (&postRelation{}).FindBy(ctx, db, query, args...)
+func (_ PostTagsQuerying) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return (&post_tagRelation{}).Minimum(ctx, db, column, dest)
 }
 
-func (_ PostsQuerying) First(ctx context.Context, db DB) (*Post, error) {
-	return (&postRelation{}).First(ctx, db)
+func (_ PostTagsQuerying) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	return (&post_tagRelation{}).Sum(ctx, db, column)
 }
 
-func (_ PostsQuerying) Last(ctx context.Context, db DB) (*Post, error) {
-	return (&postRelation{}).Last(ctx, db)
+func (_ PostTagsQuerying) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	return (&post_tagRelation{}).SumInt(ctx, db, column)
 }
 
-func (_ PostsQuerying) Limit(limit int64) PostRelation {
-	return (&postRelation{}).Limit(limit)
+func (_ PostTagsQuerying) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+	return (&post_tagRelation{}).UpdateAll(ctx, db, query, args...)
 }
 
-func (_ PostsQuerying) New() *Post {
-	return (&postRelation{}).New()
+func (_ PostTagsQuerying) All(ctx context.Context, db DB) ([]*PostTag, error) {
+	return (&post_tagRelation{}).All(ctx, db)
 }
 
-func (_ PostsQuerying) Offset(offset int64) PostRelation {
-	return (&postRelation{}).Offset(offset)
+func (_ PostTagsQuerying) Each(ctx context.Context, db DB, fn func(*PostTag) error) error {
+	return (&post_tagRelation{}).Each(ctx, db, fn)
 }
 
-func (_ PostsQuerying) Order(query string, args ...string) PostRelation {
-	return (&postRelation{}).Order(query, args...)
+func (_ PostTagsQuerying) Find(ctx context.Context, db DB, postID int64, tag string) (*PostTag, error) {
+	return (&post_tagRelation{}).Find(ctx, db, postID, tag)
 }
 
-func (_ PostsQuerying) Select(fields ...string) PostRelation {
-	return (&postRelation{}).Select(fields...)
+func (_ PostTagsQuerying) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*PostTag, error) {
+	return (&post_tagRelation{}).FindBy(ctx, db, query, args...)
+}
+
+func (_ PostTagsQuerying) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*PostTag) error) error {
+	return (&post_tagRelation{}).FindInBatches(ctx, db, batchSize, fn)
+}
+
+func (_ PostTagsQuerying) First(ctx context.Context, db DB) (*PostTag, error) {
+	return (&post_tagRelation{}).First(ctx, db)
+}
+
+func (_ PostTagsQuerying) Joins(associations ...string) PostTagRelation {
+	return (&post_tagRelation{}).Joins(associations...)
+}
+
+func (_ PostTagsQuerying) Last(ctx context.Context, db DB) (*PostTag, error) {
+	return (&post_tagRelation{}).Last(ctx, db)
+}
+
+func (_ PostTagsQuerying) Limit(limit int64) PostTagRelation {
+	return (&post_tagRelation{}).Limit(limit)
+}
+
+func (_ PostTagsQuerying) New() *PostTag {
+	return (&post_tagRelation{}).New()
+}
+
+func (_ PostTagsQuerying) Not(value interface{}, args ...interface{}) PostTagRelation {
+	return (&post_tagRelation{}).Not(value, args...)
+}
+
+func (_ PostTagsQuerying) Offset(offset int64) PostTagRelation {
+	return (&post_tagRelation{}).Offset(offset)
+}
+
+func (_ PostTagsQuerying) Or(other PostTagRelation) PostTagRelation {
+	return (&post_tagRelation{}).Or(other)
+}
+
+func (_ PostTagsQuerying) Order(query string, args ...string) PostTagRelation {
+	return (&post_tagRelation{}).Order(query, args...)
 }
 
-func (_ PostsQuerying) Take(ctx context.Context, db DB) (*Post, error) {
-	return (&postRelation{}).Take(ctx, db)
-}
+func (_ PostTagsQuerying) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostTagPage, error) {
+	return (&post_tagRelation{}).Paginate(ctx, db, cursor, pageSize)
+}
+
+func (_ PostTagsQuerying) Preload(associations ...string) PostTagRelation {
+	return (&post_tagRelation{}).Preload(associations...)
+}
+
+func (_ PostTagsQuerying) Select(fields ...string) PostTagRelation {
+	return (&post_tagRelation{}).Select(fields...)
+}
+
+func (_ PostTagsQuerying) Take(ctx context.Context, db DB) (*PostTag, error) {
+	return (&post_tagRelation{}).Take(ctx, db)
+}
+
+func (_ PostTagsQuerying) Where(value interface{}, args ...interface{}) PostTagRelation {
+	return (&post_tagRelation{}).Where(value, args...)
+}
+
+func (_ PostTagsQuerying) WhereBetween(field string, low, high interface{}) PostTagRelation {
+	return (&post_tagRelation{}).WhereBetween(field, low, high)
+}
+
+func (_ PostTagsQuerying) WhereEq(field string, value interface{}) PostTagRelation {
+	return (&post_tagRelation{}).WhereEq(field, value)
+}
+
+func (_ PostTagsQuerying) WhereIn(field string, values ...interface{}) PostTagRelation {
+	return (&post_tagRelation{}).WhereIn(field, values...)
+}
+
+func (_ PostTagsQuerying) WhereLike(field, pattern string) PostTagRelation {
+	return (&post_tagRelation{}).WhereLike(field, pattern)
+}
+
+func (_ PostTagsQuerying) WhereNot(field string, value interface{}) PostTagRelation {
+	return (&post_tagRelation{}).WhereNot(field, value)
+}
+
+func (_ PostTagsQuerying) whereExpr() rel.Expr {
+	return (&post_tagRelation{}).whereExpr()
+}
+
+func (_ PostTagsQuerying) joinExprs() []rel.Expr {
+	return (&post_tagRelation{}).joinExprs()
+}
+
+// FindBySQL returns all the PostTags selected by the given query
+func (q PostTagsQuerying) FindBySQL(ctx context.Context, db DB, query string, args ...interface{}) ([]*PostTag, error) {
+	var post_tags []*PostTag
+	err := q.EachBySQL(ctx, db, query, args, func(o *PostTag) error {
+		post_tags = append(post_tags, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post_tags, nil
+}
+
+// EachBySQL calls fn for each of the PostTags selected by the given
+// query, as they're read from the database
+func (_ PostTagsQuerying) EachBySQL(ctx context.Context, db DB, query string, args []interface{}, fn func(*PostTag) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	row := &PostTag{}
+	row.persisted = true
+	fields, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	ptrs, err := row.pointersForFields(fields)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+
+		o := &PostTag{}
+		*o = *row
+		o.saved()
+
+		if err := fn(o); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// InsertAll inserts the records with as few statements as the limit on bind
+// parameters allows. Unlike Save, it doesn't validate the records or call
+// hooks. When a statement fails, the rows of the ones before it
+// stay inserted; use a Transaction to insert all or nothing.
+func (_ PostTagsQuerying) InsertAll(ctx context.Context, db DB, records []*PostTag) error {
+	return insertPostTags(ctx, db, records, nil)
+}
+
+// UpsertAll inserts the records, or updates the rows that have the same
+// values for the conflict columns, which need a unique index. Those are the
+// primary key if none are given. Like InsertAll, it doesn't validate the
+// records or call hooks. The records are read back from the rows afterwards,
+// as existing rows keep their keys and creation timestamps.
+func (_ PostTagsQuerying) UpsertAll(ctx context.Context, db DB, records []*PostTag, conflictColumns ...string) error {
+	primaryKey := []string{"post_id", "tag"}
+	if len(conflictColumns) == 0 {
+		conflictColumns = primaryKey
+	}
+	for _, column := range conflictColumns {
+		if (&PostTag{}).fieldPointerForColumn(column) == nil {
+			return errors.Errorf("unknown column %q", column)
+		}
+	}
+
+	onConflict := &rel.OnConflict{Columns: conflictColumns}
+	for _, column := range []string{} {
+		if !containsColumn(conflictColumns, column) {
+			onConflict.Update = append(onConflict.Update, rel.Assignment{
+				Field: rel.Field{column},
+				Value: rel.Excluded{column},
+			})
+		}
+	}
 
-func (_ PostsQuerying) Where(value interface{}, args ...interface{}) PostRelation {
-	return (&postRelation{}).Where(value, args...)
+	return insertPostTags(ctx, db, records, onConflict)
 }
 
-func (_ PostsQuerying) WhereEq(field string, value interface{}) PostRelation {
-	return (&postRelation{}).WhereEq(field, value)
-}
+// readPostTagsRows copies the rows with the same values for the
+// columns into the records, as upserted rows can keep values of the existing
+// ones. The rows an upsert returned are used first, the others are selected.
+func readPostTagsRows(ctx context.Context, db DB, records, returned []*PostTag, columns []string) error {
+	keyOf := func(o *PostTag) string {
+		values := make([]interface{}, len(columns))
+		for i, column := range columns {
+			values[i] = o.valueForColumn(column)
+		}
+		key, err := json.Marshal(values)
+		if err != nil {
+			panic(err)
+		}
+		return string(key)
+	}
 
-// FindBySQL returns all the Posts selected by the given query
-func (_ PostsQuerying) FindBySQL(ctx context.Context, db DB, query string, args ...interface{}) ([]*Post, error) {
-	var posts []*Post
-	rows, err := db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
+	byKey := make(map[string]*PostTag, len(records))
+	for _, row := range returned {
+		byKey[keyOf(row)] = row
+	}
+	var missing []*PostTag
+	for _, o := range records {
+		if _, ok := byKey[keyOf(o)]; !ok {
+			missing = append(missing, o)
+		}
 	}
-	defer rows.Close()
 
-	row := &Post{}
-	row.persisted = true
-	fields, err := rows.Columns()
-	if err != nil {
-		return nil, err
+	size := Dialect.MaxParams() / len(columns)
+	for len(missing) > 0 {
+		chunk := missing
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		missing = missing[len(chunk):]
+
+		matches := make(rel.Or, len(chunk))
+		for i, o := range chunk {
+			match := make(rel.And, len(columns))
+			for j, column := range columns {
+				match[j] = rel.Equality{
+					Field: rel.Field{"post_tags." + column},
+					Value: rel.BindParam{Value: o.valueForColumn(column)},
+				}
+			}
+			matches[i] = match
+		}
+		rows, err := (&post_tagRelation{whereClause: []rel.Expr{matches}}).All(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			byKey[keyOf(row)] = row
+		}
 	}
-	ptrs, err := row.pointersForFields(fields)
-	if err != nil {
-		return nil, err
+
+	for _, o := range records {
+		row, ok := byKey[keyOf(o)]
+		if !ok {
+			return errors.New("upserted row not found")
+		}
+		o.PostID = row.PostID
+		o.Tag = row.Tag
+		o.persisted = true
+		o.saved()
 	}
+	return nil
+}
 
-	for rows.Next() {
-		if err := rows.Scan(ptrs...); err != nil {
-			return nil, err
+// insertPostTags inserts the records in statements of as many rows as fit,
+// handling conflicts with onConflict if it's set. Upserted records are read back
+// from their rows.
+func insertPostTags(ctx context.Context, db DB, records []*PostTag, onConflict *rel.OnConflict) error {
+	var columns []string
+	columns = append(columns, "post_id")
+	columns = append(columns, "tag")
+	size := Dialect.MaxParams() / len(columns)
+
+	for len(records) > 0 {
+		chunk := records
+		if len(chunk) > size {
+			chunk = chunk[:size]
 		}
+		records = records[len(chunk):]
 
-		o := &Post{}
-		*o = *row
+		stmt := &rel.InsertStatement{
+			Dialect:    Dialect,
+			Table:      "post_tags",
+			Columns:    columns,
+			OnConflict: onConflict,
+		}
+		for _, o := range chunk {
+			row := make([]rel.Expr, 0, len(columns))
+			row = append(row, &rel.BindParam{Value: o.PostID})
+			row = append(row, &rel.BindParam{Value: o.Tag})
+			stmt.Rows = append(stmt.Rows, row)
+		}
 
-		o.old.ID = o.ID
-		o.old.UserID = o.UserID
-		o.old.Body = o.Body
+		// Existing rows keep some of their values, so upserted rows are read back
+		if onConflict != nil {
+			var returned []*PostTag
+			if Dialect.Returning() {
+				stmt.Returning = post_tagColumns
+				query, values := stmt.Build()
+				var err error
+				if returned, err = PostTags().FindBySQL(ctx, db, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			} else {
+				query, values := stmt.Build()
+				if _, err := db.ExecContext(ctx, query, values...); err != nil {
+					return errors.Wrapf(err, "executing %q", query)
+				}
+			}
+			if err := readPostTagsRows(ctx, db, chunk, returned, onConflict.Columns); err != nil {
+				return err
+			}
+			continue
+		}
 
-		posts = append(posts, o)
+		query, values := stmt.Build()
+		if _, err := db.ExecContext(ctx, query, values...); err != nil {
+			return errors.Wrapf(err, "executing %q", query)
+		}
+
+		for _, o := range chunk {
+			o.persisted = true
+			o.saved()
+		}
 	}
 
-	return posts, rows.Err()
+	return nil
 }
 
 // CountBySQL executes the given query, giving a count
-func (_ PostsQuerying) CountBySQL(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+func (_ PostTagsQuerying) CountBySQL(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
 	var count int64
 	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
-type postRelation struct {
+type post_tagRelation struct {
 	fields      []string
+	joins       []rel.Expr
+	preloads    []string
 	whereClause []rel.Expr
 	orderValues []rel.Expr
 	limit       int64
 	offset      int64
 }
 
-func (q *postRelation) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
+func (q *post_tagRelation) UpdateAll(ctx context.Context, db DB, query string, args ...interface{}) (int64, error) {
 	clauses := []rel.Expr{rel.Literal{Text: query, Params: args}}
 
 	stmt := &rel.UpdateStatement{
-		Table:  "posts",
-		Wheres: q.whereClause,
-		Values: clauses,
+		Dialect: Dialect,
+		Table:   "post_tags",
+		Wheres:  q.keyedWheres(),
+		Values:  clauses,
 	}
 
 	query, values := stmt.Build()
@@ -1029,34 +7196,138 @@ func (q *postRelation) UpdateAll(ctx context.Context, db DB, query string, args
 	return res.RowsAffected()
 }
 
-func (q *postRelation) ToSQL() (query string, args []interface{}) {
-	fields := q.columnFields()
-	columns := make([]rel.Expr, 0, len(fields))
-	for _, field := range fields {
-		columns = append(columns, &rel.Literal{Text: field})
-	}
-	s := rel.SelectStatement{
-		Columns: columns,
-		Table:   "posts",
+func (q *post_tagRelation) ToSQL() (query string, args []interface{}) {
+	return q.selectStatement().Build()
+}
+
+func (q *post_tagRelation) selectStatement() *rel.SelectStatement {
+	return &rel.SelectStatement{
+		Dialect: Dialect,
+		Columns: q.columns(),
+		Table:   "post_tags",
+		Joins:   q.joins,
 		Wheres:  q.whereClause,
 		Orders:  q.orderValues,
 		Limit:   q.limit,
 		Offset:  q.offset,
 	}
-	return s.Build()
 }
 
-func (q *postRelation) Count(ctx context.Context, db DB) (int64, error) {
-	q.fields = []string{"COUNT(*)"}
+// keyedWheres are the conditions for updating or deleting the relation's
+// records. Those statements can't join, so with joins the records are
+// matched by their primary key instead.
+func (q *post_tagRelation) keyedWheres() []rel.Expr {
+	if len(q.joins) == 0 {
+		return q.whereClause
+	}
 
-	query, args := q.ToSQL()
-	return Posts().CountBySQL(ctx, db, query, args...)
+	keys := rel.ExprList{
+		rel.Field{"post_tags.post_id"},
+		rel.Field{"post_tags.tag"},
+	}
+	// MySQL can't select from the table that's changed in a subquery, unless
+	// it's wrapped in a derived table
+	return []rel.Expr{rel.In{
+		Left: rel.Grouping{Expr: keys},
+		Right: rel.ExprList{&rel.SelectStatement{
+			Columns: []rel.Expr{
+				rel.Field{"keys.post_id"},
+				rel.Field{"keys.tag"},
+			},
+			Table: "keys",
+			From: &rel.SelectStatement{
+				Columns: keys,
+				Table:   "post_tags",
+				Joins:   q.joins,
+				Wheres:  q.whereClause,
+			},
+		}},
+	}}
 }
 
-func (q *postRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
+func (q *post_tagRelation) Count(ctx context.Context, db DB) (int64, error) {
+	var count int64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "COUNT"}, &count)
+	return count, err
+}
+
+func (q *post_tagRelation) CountBy(ctx context.Context, db DB, column string) (map[interface{}]int64, error) {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{rel.Field{column}, rel.Aggregate{Func: "COUNT"}}
+	s.Groups = []rel.Expr{rel.Field{column}}
+	s.Orders = nil
+
+	query, args := s.Build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[interface{}]int64)
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		// Text can come back as bytes, which can't be a map key
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		counts[value] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (q *post_tagRelation) Sum(ctx context.Context, db DB, column string) (float64, error) {
+	var sum sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Float64, err
+}
+
+func (q *post_tagRelation) SumInt(ctx context.Context, db DB, column string) (int64, error) {
+	var sum sql.NullInt64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "SUM", Expr: rel.Field{column}}, &sum)
+	return sum.Int64, err
+}
+
+func (q *post_tagRelation) Average(ctx context.Context, db DB, column string) (float64, error) {
+	var avg sql.NullFloat64
+	err := q.aggregate(ctx, db, rel.Aggregate{Func: "AVG", Expr: rel.Field{column}}, &avg)
+	return avg.Float64, err
+}
+
+func (q *post_tagRelation) Minimum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MIN", Expr: rel.Field{column}}, dest)
+}
+
+func (q *post_tagRelation) Maximum(ctx context.Context, db DB, column string, dest interface{}) error {
+	return q.aggregate(ctx, db, rel.Aggregate{Func: "MAX", Expr: rel.Field{column}}, dest)
+}
+
+// aggregate selects a single aggregate over the relation's records into dest
+func (q *post_tagRelation) aggregate(ctx context.Context, db DB, aggregate rel.Aggregate, dest interface{}) error {
+	s := q.selectStatement()
+	s.Columns = []rel.Expr{aggregate}
+	// Ordering only makes sense for the records themselves
+	s.Orders = nil
+
+	query, args := s.Build()
+	// Aggregates lose the column's type, so SQLite returns times as text
+	switch dest.(type) {
+	case *time.Time, *sql.NullTime, **time.Time:
+		dest = timeScanner{dest: dest}
+	}
+	return db.QueryRowContext(ctx, query, args...).Scan(dest)
+}
+
+func (q *post_tagRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
 	s := rel.DeleteStatement{
-		Table:  "posts",
-		Wheres: q.whereClause,
+		Dialect: Dialect,
+		Table:   "post_tags",
+		Wheres:  q.keyedWheres(),
 	}
 
 	query, args := s.Build()
@@ -1069,7 +7340,8 @@ func (q *postRelation) DeleteAll(ctx context.Context, db DB) (int64, error) {
 	return res.RowsAffected()
 }
 
-func (q *postRelation) Where(value interface{}, args ...interface{}) PostRelation {
+func (q *post_tagRelation) Where(value interface{}, args ...interface{}) PostTagRelation {
+	q = q.clone()
 	clauses, err := rel.UnpackWhere(value, args...)
 	if err != nil {
 		panic(err)
@@ -1080,7 +7352,8 @@ func (q *postRelation) Where(value interface{}, args ...interface{}) PostRelatio
 	return q
 }
 
-func (q *postRelation) WhereEq(field string, value interface{}) PostRelation {
+func (q *post_tagRelation) WhereEq(field string, value interface{}) PostTagRelation {
+	q = q.clone()
 	q.whereClause = append(q.whereClause, rel.Equality{
 		Field: rel.Field{field},
 		Value: rel.BindParam{value},
@@ -1089,17 +7362,129 @@ func (q *postRelation) WhereEq(field string, value interface{}) PostRelation {
 	return q
 }
 
-func (q *postRelation) Limit(limit int64) PostRelation {
+func (q *post_tagRelation) WhereBetween(field string, low, high interface{}) PostTagRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Between{
+		Field: rel.Field{field},
+		Low:   rel.BindParam{low},
+		High:  rel.BindParam{high},
+	})
+
+	return q
+}
+
+func (q *post_tagRelation) WhereIn(field string, values ...interface{}) PostTagRelation {
+	q = q.clone()
+	params := make(rel.ExprList, len(values))
+	for i, value := range values {
+		params[i] = rel.BindParam{value}
+	}
+	q.whereClause = append(q.whereClause, rel.In{
+		Left:  rel.Field{field},
+		Right: params,
+	})
+
+	return q
+}
+
+func (q *post_tagRelation) WhereLike(field, pattern string) PostTagRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.Like{
+		Field:   rel.Field{field},
+		Pattern: rel.BindParam{pattern},
+	})
+
+	return q
+}
+
+func (q *post_tagRelation) WhereNot(field string, value interface{}) PostTagRelation {
+	q = q.clone()
+	q.whereClause = append(q.whereClause, rel.NotEqual{
+		Field: rel.Field{field},
+		Value: rel.BindParam{value},
+	})
+
+	return q
+}
+
+func (q *post_tagRelation) Not(value interface{}, args ...interface{}) PostTagRelation {
+	q = q.clone()
+	clauses, err := rel.UnpackWhere(value, args...)
+	if err != nil {
+		panic(err)
+	}
+
+	q.whereClause = append(q.whereClause, rel.Not{Expr: rel.And(clauses)})
+
+	return q
+}
+
+func (q *post_tagRelation) Or(other PostTagRelation) PostTagRelation {
+	if !sameJoins(q.joins, other.joinExprs()) {
+		panic("relation passed to Or must have the same joins")
+	}
+	q = q.clone()
+	q.whereClause = []rel.Expr{rel.Or{q.whereExpr(), other.whereExpr()}}
+
+	return q
+}
+
+// clone copies the relation, so the methods building on it leave it as it
+// is. The slices are capped, so appending to them copies them as well.
+func (q *post_tagRelation) clone() *post_tagRelation {
+	c := *q
+	c.fields = c.fields[:len(c.fields):len(c.fields)]
+	c.joins = c.joins[:len(c.joins):len(c.joins)]
+	c.preloads = c.preloads[:len(c.preloads):len(c.preloads)]
+	c.whereClause = c.whereClause[:len(c.whereClause):len(c.whereClause)]
+	c.orderValues = c.orderValues[:len(c.orderValues):len(c.orderValues)]
+	return &c
+}
+
+// whereExpr combines the conditions of the relation into a single expression
+func (q *post_tagRelation) whereExpr() rel.Expr {
+	return rel.And(q.whereClause)
+}
+
+func (q *post_tagRelation) joinExprs() []rel.Expr {
+	return q.joins
+}
+
+func (q *post_tagRelation) Joins(associations ...string) PostTagRelation {
+	q = q.clone()
+	for _, association := range associations {
+		switch association {
+		case "posts":
+			q.joins = append(q.joins, rel.InnerJoin{
+				Table: "posts",
+				On: rel.Equality{
+					Field: rel.Field{"posts.id"},
+					Value: rel.Field{"post_tags.post_id"},
+				},
+			})
+		default:
+			panic(fmt.Sprintf("unknown association %q", association))
+		}
+	}
+
+	return q
+}
+
+func (q *post_tagRelation) Limit(limit int64) PostTagRelation {
+	q = q.clone()
 	q.limit = limit
 	return q
 }
 
-func (q *postRelation) New() *Post {
-	o := &Post{}
+func (q *post_tagRelation) New() *PostTag {
+	o := &PostTag{}
 	for _, w := range q.whereClause {
 		if eq, ok := w.(rel.Equality); ok {
-			if bind, ok := eq.Value.(rel.BindParam); ok {
-				o.assignField(eq.Field.Name, bind.Value)
+			name := strings.TrimPrefix(eq.Field.Name, "post_tags.")
+			if eq.Value == nil {
+				o.assignField(name, nil)
+			} else if bind, ok := eq.Value.(rel.BindParam); ok {
+				o.assignField(name, bind.Value)
 			}
 		}
 	}
@@ -1107,34 +7492,133 @@ func (q *postRelation) New() *Post {
 	return o
 }
 
-func (q *postRelation) Select(fields ...string) PostRelation {
+func (q *post_tagRelation) Paginate(ctx context.Context, db DB, cursor Cursor, pageSize int) (*PostTagPage, error) {
+	if pageSize <= 0 {
+		return nil, errors.Errorf("invalid page size %d", pageSize)
+	}
+	if q.limit != 0 || q.offset != 0 {
+		return nil, errors.New("Paginate can't be used with a limit or offset")
+	}
+
+	keys, err := parseOrder("post_tags", q.orderValues)
+	if err != nil {
+		return nil, err
+	}
+	edge := &PostTag{}
+	for _, key := range keys {
+		if !edge.sortable(key.column) {
+			return nil, errors.Errorf("can't paginate by %q", key.column)
+		}
+	}
+	keys = appendSortKey(keys, "post_id")
+	keys = appendSortKey(keys, "tag")
+
+	page := q.clone()
+	page.limit = int64(pageSize) + 1
+	// The cursors need the values of the keys
+	if page.fields != nil {
+		for _, key := range keys {
+			page.fields = append(page.fields, "post_tags."+key.column)
+		}
+	}
+	var position cursorPosition
+	if cursor != "" {
+		if position, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			value, ok := position.Values[key.column]
+			if !ok || json.Unmarshal(value, edge.fieldPointerForColumn(key.column)) != nil {
+				return nil, errors.Errorf("invalid cursor %q", cursor)
+			}
+		}
+		page.whereClause = append(page.whereClause, seek("post_tags", keys, edge.valueForColumn, position.Before))
+	}
+	page.orderValues = nil
+	for _, key := range keys {
+		field := rel.Field{Name: "post_tags." + key.column}
+		if key.descending != position.Before {
+			page.orderValues = append(page.orderValues, rel.Descending{Expr: field})
+		} else {
+			page.orderValues = append(page.orderValues, rel.Ascending{Expr: field})
+		}
+	}
+
+	records, err := page.All(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	more := len(records) > pageSize
+	if more {
+		records = records[:pageSize]
+	}
+	if position.Before {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	result := &PostTagPage{Records: records}
+	if len(records) == 0 {
+		return result, nil
+	}
+	if more && !position.Before || cursor != "" && position.Before {
+		result.Next = records[len(records)-1].cursor(keys, false)
+	}
+	if more && position.Before || cursor != "" && !position.Before {
+		result.Previous = records[0].cursor(keys, true)
+	}
+	return result, nil
+}
+
+func (q *post_tagRelation) Preload(associations ...string) PostTagRelation {
+	q = q.clone()
+	q.preloads = append(q.preloads, associations...)
+	return q
+}
+
+func (q *post_tagRelation) Select(fields ...string) PostTagRelation {
+	q = q.clone()
 	q.fields = append(q.fields, fields...)
 	return q
 }
 
-func (q *postRelation) Offset(offset int64) PostRelation {
+func (q *post_tagRelation) Offset(offset int64) PostTagRelation {
+	q = q.clone()
 	q.offset = offset
 	return q
 }
 
-func (q *postRelation) columnFields() []string {
+func (q *post_tagRelation) columns() []rel.Expr {
 	if q.fields == nil {
-		return []string{
-			"id",
-			"user_id",
-			"body",
+		return []rel.Expr{
+			rel.Field{"post_tags.post_id"},
+			rel.Field{"post_tags.tag"},
 		}
-	} else {
-		return q.fields
 	}
+
+	columns := make([]rel.Expr, 0, len(q.fields))
+	for _, field := range q.fields {
+		columns = append(columns, &rel.Literal{Text: field})
+	}
+	return columns
 }
 
-func (q *postRelation) All(ctx context.Context, db DB) ([]*Post, error) {
+func (q *post_tagRelation) All(ctx context.Context, db DB) ([]*PostTag, error) {
 	query, args := q.ToSQL()
-	return Posts().FindBySQL(ctx, db, query, args...)
+	records, err := PostTags().FindBySQL(ctx, db, query, args...)
+	if err != nil || len(q.preloads) == 0 {
+		return records, err
+	}
+
+	if err := preloadPostTags(ctx, db, records, q.preloads); err != nil {
+		return nil, err
+	}
+	return records, nil
 }
 
-func (q *postRelation) Take(ctx context.Context, db DB) (*Post, error) {
+func (q *post_tagRelation) Take(ctx context.Context, db DB) (*PostTag, error) {
+	q = q.clone()
 	q.limit = 1
 	os, err := q.All(ctx, db)
 	if err != nil {
@@ -1148,23 +7632,78 @@ func (q *postRelation) Take(ctx context.Context, db DB) (*Post, error) {
 	return os[0], nil
 }
 
-func (q *postRelation) Find(ctx context.Context, db DB, id int64) (*Post, error) {
-	return q.FindBy(ctx, db, "id = ?", id)
+func (q *post_tagRelation) Each(ctx context.Context, db DB, fn func(*PostTag) error) error {
+	if len(q.preloads) > 0 {
+		return errors.New("Each can't preload associations, use FindInBatches")
+	}
+	query, args := q.ToSQL()
+	return PostTags().EachBySQL(ctx, db, query, args, fn)
 }
 
-func (q *postRelation) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*Post, error) {
+func (q *post_tagRelation) FindInBatches(ctx context.Context, db DB, batchSize int, fn func([]*PostTag) error) error {
+	if batchSize <= 0 {
+		return errors.Errorf("invalid batch size %d", batchSize)
+	}
+	if len(q.orderValues) > 0 || q.limit != 0 || q.offset != 0 {
+		return errors.New("FindInBatches can't be used with an order, limit or offset")
+	}
+
+	batch := q.clone()
+	batch.limit = int64(batchSize)
+	// The next batch starts after the key of the last record, so it has to be
+	// selected
+	if batch.fields != nil {
+		batch.fields = append(batch.fields, "post_tags.post_id")
+	}
+	if batch.fields != nil {
+		batch.fields = append(batch.fields, "post_tags.tag")
+	}
+	batch.orderValues = append(batch.orderValues, rel.Ascending{Expr: rel.Field{"post_tags.post_id"}})
+	batch.orderValues = append(batch.orderValues, rel.Ascending{Expr: rel.Field{"post_tags.tag"}})
+	for {
+		records, err := batch.All(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+
+		batch = batch.clone()
+		batch.whereClause = append(q.whereClause[:len(q.whereClause):len(q.whereClause)], records[len(records)-1].afterKey())
+	}
+}
+
+func (q *post_tagRelation) Find(ctx context.Context, db DB, postID int64, tag string) (*PostTag, error) {
+	return q.WhereEq("post_tags.post_id", postID).WhereEq("post_tags.tag", tag).Take(ctx, db)
+}
+
+func (q *post_tagRelation) FindBy(ctx context.Context, db DB, query string, args ...interface{}) (*PostTag, error) {
 	return q.Where(query, args...).Take(ctx, db)
 }
 
-func (q *postRelation) First(ctx context.Context, db DB) (*Post, error) {
-	return q.Order("id ASC").Take(ctx, db)
+func (q *post_tagRelation) First(ctx context.Context, db DB) (*PostTag, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Ascending{Expr: rel.Field{"post_tags.post_id"}})
+	q.orderValues = append(q.orderValues, rel.Ascending{Expr: rel.Field{"post_tags.tag"}})
+	return q.Take(ctx, db)
 }
 
-func (q *postRelation) Last(ctx context.Context, db DB) (*Post, error) {
-	return q.Order("id DESC").Take(ctx, db)
+func (q *post_tagRelation) Last(ctx context.Context, db DB) (*PostTag, error) {
+	q = q.clone()
+	q.orderValues = append(q.orderValues, rel.Descending{Expr: rel.Field{"post_tags.post_id"}})
+	q.orderValues = append(q.orderValues, rel.Descending{Expr: rel.Field{"post_tags.tag"}})
+	return q.Take(ctx, db)
 }
 
-func (q *postRelation) Order(query string, args ...string) PostRelation {
+func (q *post_tagRelation) Order(query string, args ...string) PostTagRelation {
+	q = q.clone()
 	q.orderValues = append(q.orderValues, &rel.Literal{Text: query})
 
 	for i := 0; i < len(args); i++ {
@@ -1173,3 +7712,61 @@ func (q *postRelation) Order(query string, args ...string) PostRelation {
 
 	return q
 }
+
+// preloadPostTags fills the association caches of the records for the
+// given association paths
+func preloadPostTags(ctx context.Context, db DB, records []*PostTag, paths []string) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// The nested paths are preloaded along with their first association
+	var names []string
+	nested := make(map[string][]string)
+	for _, path := range paths {
+		name, rest := path, ""
+		if i := strings.Index(path, "."); i >= 0 {
+			name, rest = path[:i], path[i+1:]
+		}
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = []string{}
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+
+	for _, name := range names {
+		switch name {
+		case "Post":
+			var keys []interface{}
+			seen := make(map[int64]bool)
+			for _, o := range records {
+				if key := o.PostID; !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+
+			byKey := make(map[int64]*Post, len(keys))
+			for _, chunk := range keyChunks(keys) {
+				parents, err := Posts().WhereIn("posts.id", chunk...).Preload(nested[name]...).All(ctx, db)
+				if err != nil {
+					return err
+				}
+				for _, p := range parents {
+					byKey[p.ID] = p
+				}
+			}
+			for _, o := range records {
+				o.associations.Posts.record = byKey[o.PostID]
+				o.associations.Posts.loaded = true
+			}
+		default:
+			return errors.Errorf("post_tags: unknown association %q", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BeforeSave normalizes the tag, so the same tag isn't added twice with
+// different capitalization
+func (t *PostTag) BeforeSave(ctx context.Context, db DB) error {
+	t.Tag = strings.ToLower(strings.TrimSpace(t.Tag))
+	if t.Tag == "" {
+		return errors.New("tag can't be empty")
+	}
+	return nil
+}
+
+// BeforeDelete takes the posts out of the category, as they're kept
+func (c *Category) BeforeDelete(ctx context.Context, db DB) error {
+	_, err := c.Posts().UpdateAll(ctx, db, "category_id = NULL")
+	return err
+}
@@ -1 +1,3 @@
 package example
+
+//go:generate go run bou.ke/orm generate -schema schema.json -pkg db -out db/db.generated.go
@@ -1,7 +1,12 @@
 package example
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -44,6 +49,38 @@ func TestWhereScope(t *testing.T) {
 	require.Equal(t, "Tables", u.LastName)
 }
 
+func TestNullableColumn(t *testing.T) {
+	defer clear()
+	u := createUser(t)
+
+	u, err := db.Users().Find(ctx, d, u.ID)
+	require.NoError(t, err)
+	require.False(t, u.Email.Valid)
+
+	u.Email = sql.NullString{String: "bobby@example.com", Valid: true}
+	require.NoError(t, u.Save(ctx, d))
+	createUser(t)
+
+	c, err := db.Users().WhereEq("email", nil).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, c)
+
+	u, err = db.Users().WhereEq("email", "bobby@example.com").Take(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "bobby@example.com", u.Email.String)
+
+	u.Email = sql.NullString{}
+	require.NoError(t, u.Save(ctx, d))
+	c, err = db.Users().Where(map[string]interface{}{"email": sql.NullString{}}).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, c)
+
+	u = db.Users().WhereEq("email", "bobby@example.com").New()
+	require.Equal(t, sql.NullString{String: "bobby@example.com", Valid: true}, u.Email)
+	u = db.Users().WhereEq("email", nil).New()
+	require.False(t, u.Email.Valid)
+}
+
 func TestCreatePostUnderUser(t *testing.T) {
 	defer clear()
 	u := createUser(t)
@@ -128,6 +165,882 @@ func TestUpdateAll(t *testing.T) {
 	require.EqualValues(t, 2, count)
 }
 
+func TestOrNot(t *testing.T) {
+	defer clear()
+
+	for _, name := range []string{"Bouke", "Jane", "John"} {
+		u := createUser(t)
+		u.FirstName = name
+		u.LastName = "Tables"
+		require.NoError(t, u.Save(ctx, d))
+	}
+
+	count, err := db.Users().WhereEq("first_name", "Bouke").Or(db.Users().Where("first_name = ?", "Jane")).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = db.Users().Where("first_name = ? OR first_name = ?", "Bouke", "Jane").WhereEq("last_name", "Tables").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = db.Users().Not(map[string]string{"first_name": "Bouke"}).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = db.Users().Not("first_name = ?", "Bouke").Or(db.Users().WhereEq("first_name", "Bouke")).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count)
+
+	u, err := db.Users().WhereEq("first_name", "Jane").Take(ctx, d)
+	require.NoError(t, err)
+	p := u.Posts().New()
+	p.Body = "Hello"
+	require.NoError(t, p.Save(ctx, d))
+	count, err = db.Users().Joins("posts").WhereEq("first_name", "Bouke").Or(db.Users().Joins("posts").Where("posts.body = ?", "Hello")).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	// The joins would be lost otherwise
+	require.PanicsWithValue(t, "relation passed to Or must have the same joins", func() {
+		db.Users().Or(db.Users().Joins("posts").Where("posts.body = ?", "Hello"))
+	})
+	require.Panics(t, func() {
+		db.Users().Joins("posts").Or(db.Users())
+	})
+}
+
+func TestWhereHelpers(t *testing.T) {
+	defer clear()
+
+	for _, name := range []string{"Bouke", "Jane", "John"} {
+		u := createUser(t)
+		u.FirstName = name
+		require.NoError(t, u.Save(ctx, d))
+	}
+	u, err := db.Users().WhereEq("first_name", "John").Take(ctx, d)
+	require.NoError(t, err)
+	u.Email = sql.NullString{String: "john@example.com", Valid: true}
+	require.NoError(t, u.Save(ctx, d))
+
+	count, err := db.Users().WhereNot("first_name", "Bouke").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = db.Users().WhereNot("email", nil).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	count, err = db.Users().WhereIn("first_name", "Bouke", "Jane", "Nobody").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = db.Users().WhereIn("first_name").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, count)
+
+	count, err = db.Users().WhereLike("first_name", "J%").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = db.Users().WhereLike("first_name", rel.EscapeLike("J%")).Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, count)
+
+	count, err = db.Users().WhereBetween("first_name", "Bouke", "Jane").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+}
+
+func TestJoins(t *testing.T) {
+	defer clear()
+
+	bouke := createUser(t)
+	bouke.FirstName = "Bouke"
+	require.NoError(t, bouke.Save(ctx, d))
+	for _, body := range []string{"Hello", "World"} {
+		p := bouke.Posts().New()
+		p.Body = body
+		require.NoError(t, p.Save(ctx, d))
+	}
+	jane := createUser(t)
+	jane.FirstName = "Jane"
+	require.NoError(t, jane.Save(ctx, d))
+
+	users, err := db.Users().Joins("posts").WhereEq("posts.body", "World").All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, bouke.ID, users[0].ID)
+	require.Equal(t, "Bouke", users[0].FirstName)
+
+	count, err := db.Users().Joins("posts").Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	posts, err := db.Posts().Joins("users").WhereEq("users.first_name", "Bouke").Order("posts.id").All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	require.Equal(t, "Hello", posts[0].Body)
+
+	p, err := bouke.Posts().Joins("users").Last(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "World", p.Body)
+
+	count, err = db.Posts().Joins("users").WhereEq("users.first_name", "Bouke").WhereEq("body", "Hello").DeleteAll(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+	count, err = db.Posts().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	require.Panics(t, func() { db.Users().Joins("comments") })
+}
+
+func TestAggregates(t *testing.T) {
+	defer clear()
+
+	sum, err := db.Users().Sum(ctx, d, "id")
+	require.NoError(t, err)
+	require.Zero(t, sum)
+	var first sql.NullString
+	require.NoError(t, db.Users().Minimum(ctx, d, "first_name", &first))
+	require.False(t, first.Valid)
+	var oldest sql.NullTime
+	require.NoError(t, db.Posts().Minimum(ctx, d, "created_at", &oldest))
+	require.False(t, oldest.Valid)
+
+	var ids []int64
+	for _, name := range []string{"Jane", "Bouke", "John"} {
+		u := createUser(t)
+		u.FirstName = name
+		require.NoError(t, u.Save(ctx, d))
+		ids = append(ids, u.ID)
+	}
+	var p *db.Post
+	for _, id := range []int64{ids[0], ids[0], ids[1]} {
+		p = db.Posts().New()
+		p.UserID = id
+		require.NoError(t, p.Save(ctx, d))
+	}
+
+	sum, err = db.Users().Sum(ctx, d, "id")
+	require.NoError(t, err)
+	require.EqualValues(t, ids[0]+ids[1]+ids[2], sum)
+
+	avg, err := db.Users().WhereIn("id", ids[0], ids[1]).Average(ctx, d, "users.id")
+	require.NoError(t, err)
+	require.EqualValues(t, float64(ids[0]+ids[1])/2, avg)
+
+	require.NoError(t, db.Users().Order("id").Minimum(ctx, d, "first_name", &first))
+	require.Equal(t, "Bouke", first.String)
+	var last string
+	require.NoError(t, db.Users().Maximum(ctx, d, "first_name", &last))
+	require.Equal(t, "John", last)
+	var newest time.Time
+	require.NoError(t, db.Posts().Maximum(ctx, d, "created_at", &newest))
+	require.True(t, newest.Equal(p.CreatedAt))
+
+	counts, err := db.Posts().CountBy(ctx, d, "user_id")
+	require.NoError(t, err)
+	require.Equal(t, map[interface{}]int64{ids[0]: 2, ids[1]: 1}, counts)
+
+	counts, err = db.Users().Joins("posts").CountBy(ctx, d, "first_name")
+	require.NoError(t, err)
+	require.Equal(t, map[interface{}]int64{"Jane": 2, "Bouke": 1}, counts)
+
+	// Beyond 2^53, only SumInt is exact
+	big := db.Users().New()
+	big.ID = 1<<53 + 1
+	require.NoError(t, big.Save(ctx, d))
+	total, err := db.Users().WhereIn("id", ids[0], big.ID).SumInt(ctx, d, "id")
+	require.NoError(t, err)
+	require.Equal(t, ids[0]+big.ID, total)
+	total, err = db.Users().WhereEq("id", 0).SumInt(ctx, d, "id")
+	require.NoError(t, err)
+	require.Zero(t, total)
+}
+
+func TestHooks(t *testing.T) {
+	defer clear()
+
+	u := createUser(t)
+	p := u.Posts().New()
+	require.NoError(t, p.Save(ctx, d))
+
+	tag := p.PostTags().New()
+	tag.Tag = " Go "
+	require.NoError(t, tag.Save(ctx, d))
+	require.Equal(t, "go", tag.Tag)
+	_, err := db.PostTags().Find(ctx, d, p.ID, "go")
+	require.NoError(t, err)
+
+	tag = p.PostTags().New()
+	require.EqualError(t, tag.Save(ctx, d), "tag can't be empty")
+	n, err := p.PostTags().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	c := db.Categories().New()
+	c.Slug = "news"
+	require.NoError(t, c.Save(ctx, d))
+	p.CategoryID = sql.NullString{String: "news", Valid: true}
+	require.NoError(t, p.Save(ctx, d))
+
+	tx, err := d.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, c.Delete(ctx, tx))
+	require.NoError(t, tx.Rollback())
+
+	p, err = db.Posts().Find(ctx, d, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, "news", p.CategoryID.String)
+
+	require.NoError(t, c.Delete(ctx, d))
+	p, err = db.Posts().Find(ctx, d, p.ID)
+	require.NoError(t, err)
+	require.False(t, p.CategoryID.Valid)
+}
+
+func TestValidations(t *testing.T) {
+	defer clear()
+
+	u := createUser(t)
+	u.Email = sql.NullString{String: "john@example.com", Valid: true}
+	require.NoError(t, u.Save(ctx, d))
+	u.LastName = "Doe"
+	require.NoError(t, u.Save(ctx, d))
+
+	other := db.Users().New()
+	other.Email = sql.NullString{String: "not an email", Valid: true}
+	err := other.Save(ctx, d)
+	require.Equal(t, db.ValidationErrors{"email": {"is invalid"}}, err)
+
+	other.Email = u.Email
+	require.EqualError(t, other.Save(ctx, d), "email has already been taken")
+	n, err := db.Users().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	c := db.Categories().New()
+	err = c.Save(ctx, d)
+	require.Equal(t, db.ValidationErrors{"slug": {"can't be blank"}}, err)
+	c.Slug = "Big News"
+	require.EqualError(t, c.Save(ctx, d), "slug is invalid")
+	c.Slug = "big-news-from-all-around-the-world"
+	require.EqualError(t, c.Save(ctx, d), "slug is too long (maximum is 32 characters)")
+	c.Slug = "big-news"
+	require.NoError(t, c.Save(ctx, d))
+}
+
+func TestTimestamps(t *testing.T) {
+	defer clear()
+	defer func(now func() time.Time) { db.Now = now }(db.Now)
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Now = func() time.Time { return created }
+	u := createUser(t)
+	p := u.Posts().New()
+	p.Body = "Hello"
+	require.NoError(t, p.Save(ctx, d))
+	require.Equal(t, created, p.CreatedAt)
+	require.Equal(t, created, p.UpdatedAt)
+
+	updated := created.Add(time.Hour)
+	db.Now = func() time.Time { return updated }
+	p.Body = "World"
+	require.NoError(t, p.Save(ctx, d))
+	p, err := db.Posts().Find(ctx, d, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, created, p.CreatedAt.UTC())
+	require.Equal(t, updated, p.UpdatedAt.UTC())
+
+	touched := updated.Add(time.Hour)
+	db.Now = func() time.Time { return touched }
+	p.Body = "Unsaved"
+	require.NoError(t, p.Touch(ctx, d))
+	require.Equal(t, touched, p.UpdatedAt)
+	p, err = db.Posts().Find(ctx, d, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, "World", p.Body)
+	require.Equal(t, touched, p.UpdatedAt.UTC())
+
+	bumped := touched.Add(time.Hour)
+	db.Now = func() time.Time { return bumped }
+	n, err := db.Posts().UpdateAll(ctx, d, "body = ?", "Bumped")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	p, err = db.Posts().Find(ctx, d, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, bumped, p.UpdatedAt.UTC())
+
+	// Expressions have no declared type, so SQLite returns the text
+	posts, err := db.Posts().FindBySQL(ctx, d, "SELECT id, '2021-06-07 08:09:10' AS created_at FROM posts")
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.Equal(t, time.Date(2021, 6, 7, 8, 9, 10, 0, time.UTC), posts[0].CreatedAt)
+}
+
+func TestTransaction(t *testing.T) {
+	defer clear()
+
+	err := db.Transaction(ctx, d, func(tx db.DB) error {
+		require.NoError(t, db.Users().New().Save(ctx, tx))
+		return db.Users().New().Save(ctx, tx)
+	})
+	require.NoError(t, err)
+	n, err := db.Users().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	failure := errors.New("failure")
+	err = db.Transaction(ctx, d, func(tx db.DB) error {
+		_, err := db.Users().DeleteAll(ctx, tx)
+		require.NoError(t, err)
+		return failure
+	})
+	require.Equal(t, failure, err)
+
+	require.Panics(t, func() {
+		db.Transaction(ctx, d, func(tx db.DB) error {
+			_, err := db.Users().DeleteAll(ctx, tx)
+			require.NoError(t, err)
+			panic("failure")
+		})
+	})
+	n, err = db.Users().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err = db.TransactionWithOptions(ctx, d, opts, func(tx db.DB) error {
+		require.NoError(t, db.Users().New().Save(ctx, tx))
+
+		err := db.Transaction(ctx, tx, func(tx db.DB) error {
+			_, err := db.Users().DeleteAll(ctx, tx)
+			require.NoError(t, err)
+			return failure
+		})
+		require.Equal(t, failure, err)
+
+		err = db.TransactionWithOptions(ctx, tx, opts, func(tx db.DB) error {
+			return nil
+		})
+		require.EqualError(t, err, "can't set the options of a nested transaction")
+
+		return db.Transaction(ctx, tx, func(tx db.DB) error {
+			return db.Users().New().Save(ctx, tx)
+		})
+	})
+	require.NoError(t, err)
+	n, err = db.Users().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, n)
+}
+
+func TestRelationsAreImmutable(t *testing.T) {
+	defer clear()
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		u := db.Users().New()
+		u.FirstName = name
+		u.LastName = "Smith"
+		require.NoError(t, u.Save(ctx, d))
+	}
+
+	base := db.Users().WhereEq("last_name", "Smith").Order("first_name")
+	n, err := base.Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, n)
+	users, err := base.All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	require.Equal(t, "Smith", users[0].LastName)
+
+	first, err := base.Take(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", first.FirstName)
+	users, err = base.All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+
+	// Both derive from base, without seeing each other's conditions
+	alice := base.WhereEq("first_name", "Alice")
+	bob := base.WhereEq("first_name", "Bob")
+	u, err := alice.Take(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", u.FirstName)
+	u, err = bob.Take(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "Bob", u.FirstName)
+
+	limited := base.Limit(1).Select("id")
+	users, err = limited.All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Empty(t, users[0].FirstName)
+	users, err = base.All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	require.Equal(t, "Alice", users[0].FirstName)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			u, err := base.WhereEq("first_name", name).Take(ctx, d)
+			require.NoError(t, err)
+			require.Equal(t, name, u.FirstName)
+		}(name)
+	}
+	wg.Wait()
+}
+
+func TestEach(t *testing.T) {
+	defer clear()
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		ids = append(ids, createUser(t).ID)
+	}
+
+	var seen []int64
+	err := db.Users().Order("id").Each(ctx, d, func(u *db.User) error {
+		seen = append(seen, u.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, ids, seen)
+
+	stop := errors.New("stop")
+	seen = nil
+	err = db.Users().Order("id").Each(ctx, d, func(u *db.User) error {
+		seen = append(seen, u.ID)
+		return stop
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, ids[:1], seen)
+
+	err = db.Users().Preload("Posts").Each(ctx, d, func(u *db.User) error {
+		return nil
+	})
+	require.EqualError(t, err, "Each can't preload associations, use FindInBatches")
+}
+
+func TestFindInBatches(t *testing.T) {
+	defer clear()
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		u := createUser(t)
+		require.NoError(t, u.Posts().New().Save(ctx, d))
+		ids = append(ids, u.ID)
+	}
+
+	cd := &countingDB{DB: d}
+	var sizes []int
+	var seen []int64
+	err := db.Users().Preload("Posts").FindInBatches(ctx, cd, 2, func(users []*db.User) error {
+		sizes = append(sizes, len(users))
+		for _, u := range users {
+			require.True(t, u.Posts().Loaded())
+			seen = append(seen, u.ID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 2, 1}, sizes)
+	require.Equal(t, ids, seen)
+	require.Equal(t, 6, cd.queries)
+
+	sizes = nil
+	err = db.Users().WhereIn("id", ids[1], ids[2], ids[3]).FindInBatches(ctx, d, 3, func(users []*db.User) error {
+		sizes = append(sizes, len(users))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, sizes)
+
+	// The keys are selected even when they're left out
+	sizes = nil
+	err = db.Users().Select("first_name").FindInBatches(ctx, d, 2, func(users []*db.User) error {
+		sizes = append(sizes, len(users))
+		if len(sizes) > 3 {
+			return errors.New("too many batches")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 2, 1}, sizes)
+
+	p, err := db.Posts().First(ctx, d)
+	require.NoError(t, err)
+	for _, tag := range []string{"a", "b", "c"} {
+		pt := p.PostTags().New()
+		pt.Tag = tag
+		require.NoError(t, pt.Save(ctx, d))
+	}
+	var tags []string
+	err = db.PostTags().FindInBatches(ctx, d, 2, func(pts []*db.PostTag) error {
+		for _, pt := range pts {
+			tags = append(tags, pt.Tag)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, tags)
+
+	err = db.Users().Order("first_name").FindInBatches(ctx, d, 2, func([]*db.User) error {
+		return nil
+	})
+	require.EqualError(t, err, "FindInBatches can't be used with an order, limit or offset")
+	err = db.Users().FindInBatches(ctx, d, 0, func([]*db.User) error {
+		return nil
+	})
+	require.EqualError(t, err, "invalid batch size 0")
+}
+
+func TestPaginate(t *testing.T) {
+	defer clear()
+
+	for _, name := range []string{"Eve", "Alice", "Dave", "Bob", "Alice", "Carol"} {
+		u := db.Users().New()
+		u.FirstName = name
+		u.LastName = "Smith"
+		require.NoError(t, u.Save(ctx, d))
+	}
+	other := createUser(t)
+
+	base := db.Users().WhereEq("last_name", "Smith").Order("first_name DESC")
+	names := func(page *db.UserPage) []string {
+		var names []string
+		for _, u := range page.Records {
+			names = append(names, u.FirstName)
+		}
+		return names
+	}
+
+	first, err := base.Paginate(ctx, d, "", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Eve", "Dave"}, names(first))
+	require.Empty(t, first.Previous)
+	require.NotEmpty(t, first.Next)
+
+	second, err := base.Paginate(ctx, d, first.Next, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Carol", "Bob"}, names(second))
+
+	// Both Alices are on the last page, ordered by id
+	last, err := base.Paginate(ctx, d, second.Next, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Alice"}, names(last))
+	require.Less(t, last.Records[0].ID, last.Records[1].ID)
+	require.Empty(t, last.Next)
+
+	page, err := base.Paginate(ctx, d, last.Previous, 2)
+	require.NoError(t, err)
+	require.Equal(t, names(second), names(page))
+	require.Equal(t, second.Next, page.Next)
+	page, err = base.Paginate(ctx, d, page.Previous, 2)
+	require.NoError(t, err)
+	require.Equal(t, names(first), names(page))
+	require.Empty(t, page.Previous)
+
+	// Records added before the cursor don't shift the following pages
+	u := db.Users().New()
+	u.FirstName = "Frank"
+	u.LastName = "Smith"
+	require.NoError(t, u.Save(ctx, d))
+	page, err = base.Paginate(ctx, d, first.Next, 2)
+	require.NoError(t, err)
+	require.Equal(t, names(second), names(page))
+
+	// The sort keys are selected even when they're left out
+	page, err = base.Select("last_name").Paginate(ctx, d, first.Next, 2)
+	require.NoError(t, err)
+	require.Equal(t, names(second), names(page))
+	require.Equal(t, second.Next, page.Next)
+
+	page, err = db.Users().Paginate(ctx, d, "", 10)
+	require.NoError(t, err)
+	require.Len(t, page.Records, 8)
+	require.Equal(t, other.ID, page.Records[6].ID)
+
+	_, err = base.Paginate(ctx, d, "nonsense", 2)
+	require.EqualError(t, err, `invalid cursor "nonsense"`)
+	_, err = db.Users().Order("last_name").Paginate(ctx, d, first.Next, 2)
+	require.EqualError(t, err, fmt.Sprintf("invalid cursor %q", first.Next))
+	_, err = db.Users().Order("lower(first_name)").Paginate(ctx, d, "", 2)
+	require.EqualError(t, err, `can't paginate by "lower(first_name)"`)
+	_, err = db.Users().Order("email").Paginate(ctx, d, "", 2)
+	require.EqualError(t, err, `can't paginate by "email"`)
+	_, err = db.Users().Limit(5).Paginate(ctx, d, "", 2)
+	require.EqualError(t, err, "Paginate can't be used with a limit or offset")
+}
+
+func TestInsertAll(t *testing.T) {
+	defer clear()
+
+	u := createUser(t)
+	var posts []*db.Post
+	for i := 0; i < 500; i++ {
+		p := u.Posts().New()
+		p.Body = fmt.Sprint(i)
+		posts = append(posts, p)
+	}
+	posts[10].ID = 100000
+
+	cd := &countingDB{DB: d}
+	require.NoError(t, db.Posts().InsertAll(ctx, cd, posts))
+	// 199 rows of 5 columns fit in the 999 bind parameters, plus one for the row with an ID
+	require.Equal(t, 4, cd.queries)
+
+	all, err := u.Posts().Order("id").All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, all, 500)
+	require.Equal(t, posts[10].ID, all[0].ID)
+	byID := make(map[int64]*db.Post)
+	for _, p := range all {
+		byID[p.ID] = p
+	}
+	for _, p := range posts {
+		require.NotZero(t, p.CreatedAt)
+		require.Equal(t, p.Body, byID[p.ID].Body)
+	}
+
+	posts[0].Body = "Changed"
+	require.NoError(t, posts[0].Save(ctx, d))
+	p, err := db.Posts().Find(ctx, d, posts[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, "Changed", p.Body)
+
+	tags := []*db.PostTag{{PostID: p.ID, Tag: "a"}, {PostID: p.ID, Tag: "b"}}
+	require.NoError(t, db.PostTags().InsertAll(ctx, d, tags))
+	n, err := p.PostTags().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	require.NoError(t, tags[1].Delete(ctx, d))
+
+	require.NoError(t, db.Posts().InsertAll(ctx, d, nil))
+
+	// PostgreSQL has no LastInsertId, so the IDs are returned
+	defer func(dialect rel.Dialect) { db.Dialect = dialect }(db.Dialect)
+	db.Dialect = rel.Postgres
+	more := []*db.Post{{UserID: u.ID, Body: "Four"}, {UserID: u.ID, Body: "Five"}}
+	require.NoError(t, db.Posts().InsertAll(ctx, d, more))
+	for _, p := range more {
+		found, err := db.Posts().Find(ctx, d, p.ID)
+		require.NoError(t, err)
+		require.Equal(t, p.Body, found.Body)
+	}
+
+	db.Dialect = noReturning{rel.Postgres}
+	err = db.Posts().InsertAll(ctx, d, []*db.Post{{UserID: u.ID, Body: "Six"}})
+	require.EqualError(t, err, "can't get the IDs of inserted rows with this dialect")
+}
+
+func TestUpsert(t *testing.T) {
+	defer clear()
+
+	u := db.Users().New()
+	u.FirstName = "John"
+	u.Email = sql.NullString{String: "john@example.com", Valid: true}
+	require.NoError(t, u.Upsert(ctx, d, "email"))
+	require.NotZero(t, u.ID)
+
+	again := db.Users().New()
+	again.FirstName = "Johnny"
+	again.Email = u.Email
+	require.NoError(t, again.Upsert(ctx, d, "email"))
+	require.Equal(t, u.ID, again.ID)
+	n, err := db.Users().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	u, err = db.Users().Find(ctx, d, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Johnny", u.FirstName)
+
+	again.LastName = "Doe"
+	require.NoError(t, again.Save(ctx, d))
+	require.EqualError(t, db.Users().New().Upsert(ctx, d), "can't upsert on the ID of records without one")
+	require.EqualError(t, u.Upsert(ctx, d, "nickname"), `unknown column "nickname"`)
+
+	defer func(now func() time.Time) { db.Now = now }(db.Now)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Now = func() time.Time { return created }
+	posts := []*db.Post{{UserID: u.ID, Body: "One"}, {UserID: u.ID, Body: "Two"}}
+	require.NoError(t, db.Posts().InsertAll(ctx, d, posts))
+
+	updated := created.Add(time.Hour)
+	db.Now = func() time.Time { return updated }
+	posts[0].Body = "Uno"
+	posts[0].CreatedAt = updated
+	require.NoError(t, db.Posts().UpsertAll(ctx, d, posts))
+	all, err := u.Posts().Order("id").All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, "Uno", all[0].Body)
+	require.Equal(t, created, all[0].CreatedAt.UTC())
+	require.Equal(t, updated, all[0].UpdatedAt.UTC())
+
+	// The records are read back, with the values the rows kept
+	defer func(dialect rel.Dialect) { db.Dialect = dialect }(db.Dialect)
+	for _, dialect := range []rel.Dialect{rel.SQLite, rel.SQLiteReturning} {
+		db.Dialect = dialect
+		posts[1].CreatedAt = updated
+		require.NoError(t, db.Posts().UpsertAll(ctx, d, posts))
+		for _, p := range posts {
+			require.Equal(t, created, p.CreatedAt.UTC())
+			require.False(t, p.IsChanged())
+		}
+
+		post := &db.Post{ID: posts[0].ID, UserID: u.ID, Body: "Uno"}
+		require.NoError(t, post.Upsert(ctx, d))
+		require.Equal(t, created, post.CreatedAt.UTC())
+		require.False(t, post.IsChanged())
+	}
+
+	tags := []*db.PostTag{{PostID: posts[0].ID, Tag: "go"}}
+	require.NoError(t, db.PostTags().UpsertAll(ctx, d, tags))
+	// Skipped rows aren't returned, so they're selected
+	require.NoError(t, db.PostTags().UpsertAll(ctx, d, tags))
+	n, err = db.PostTags().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+}
+
+// noReturning is a dialect without RETURNING
+type noReturning struct {
+	rel.Dialect
+}
+
+func (noReturning) Returning() bool {
+	return false
+}
+
+func TestSaveReadsRowBack(t *testing.T) {
+	defer func(dialect rel.Dialect) { db.Dialect = dialect }(db.Dialect)
+
+	for _, test := range []struct {
+		dialect rel.Dialect
+		queries int
+	}{
+		{rel.SQLiteReturning, 1},
+		{rel.SQLite, 2},
+	} {
+		db.Dialect = test.dialect
+		cd := &countingDB{DB: d}
+
+		u := db.Users().New()
+		u.FirstName = "Ann"
+		require.NoError(t, u.Save(ctx, cd))
+		require.NotZero(t, u.ID)
+		require.Equal(t, test.queries, cd.queries)
+
+		// Changed behind the record's back
+		_, err := d.ExecContext(ctx, "UPDATE users SET last_name = 'Smith' WHERE id = ?", u.ID)
+		require.NoError(t, err)
+
+		cd.queries = 0
+		u.FirstName = "Anna"
+		require.NoError(t, u.Save(ctx, cd))
+		require.Equal(t, "Smith", u.LastName)
+		require.Equal(t, test.queries, cd.queries)
+
+		// The row's value is what the change is compared against
+		u.LastName = ""
+		require.NoError(t, u.Save(ctx, d))
+		u, err = db.Users().Find(ctx, d, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "", u.LastName)
+
+		clear()
+	}
+}
+
+func TestDirtyTracking(t *testing.T) {
+	defer clear()
+
+	u := db.Users().New()
+	require.False(t, u.IsPersisted())
+	require.False(t, u.IsChanged())
+	u.FirstName = "Ann"
+	require.True(t, u.IsChanged())
+	require.Equal(t, []string{"first_name"}, u.ChangedColumns())
+	require.NoError(t, u.Save(ctx, d))
+	require.True(t, u.IsPersisted())
+	require.False(t, u.IsChanged())
+	require.Empty(t, u.Changes())
+
+	u.LastName = "Smith"
+	u.Email = sql.NullString{String: "ann@example.com", Valid: true}
+	require.Equal(t, []string{"last_name", "email"}, u.ChangedColumns())
+	require.Equal(t, map[string][2]interface{}{
+		"last_name": {"", "Smith"},
+		"email":     {sql.NullString{}, u.Email},
+	}, u.Changes())
+	u.Restore()
+	require.False(t, u.IsChanged())
+	require.Equal(t, "", u.LastName)
+
+	// Saving without changes doesn't write anything
+	cd := &countingDB{DB: d}
+	require.NoError(t, u.Save(ctx, cd))
+	require.Equal(t, 0, cd.queries)
+
+	p := u.Posts().New()
+	p.Body = "Hello"
+	require.NoError(t, p.Save(ctx, d))
+	posts, err := u.Posts().All(ctx, d)
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+
+	_, err = d.ExecContext(ctx, "UPDATE users SET first_name = 'Anna' WHERE id = ?", u.ID)
+	require.NoError(t, err)
+	u.LastName = "Jones"
+	require.NoError(t, u.Reload(ctx, d))
+	require.Equal(t, "Anna", u.FirstName)
+	require.Equal(t, "", u.LastName)
+	require.False(t, u.IsChanged())
+	require.False(t, u.Posts().Loaded())
+
+	require.EqualError(t, db.Users().New().Reload(ctx, d), "record not persisted")
+	require.NoError(t, p.Delete(ctx, d))
+	require.True(t, p.IsDeleted())
+	require.EqualError(t, p.Reload(ctx, d), "record deleted")
+
+	_, err = db.Users().DeleteAll(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, db.ErrNotFound, u.Reload(ctx, d))
+}
+
+func TestBytesNullVersusEmpty(t *testing.T) {
+	defer clear()
+
+	u := db.Users().New()
+	require.NoError(t, u.Save(ctx, d))
+	require.Nil(t, u.Avatar)
+
+	// NULL and an empty blob are different values
+	u.Avatar = []byte{}
+	require.True(t, u.IsChanged())
+	require.NoError(t, u.Save(ctx, d))
+	count, err := db.Users().WhereNot("avatar", nil).Count(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	u.Avatar = nil
+	require.Equal(t, []string{"avatar"}, u.ChangedColumns())
+	u.Restore()
+	require.NotNil(t, u.Avatar)
+	require.Empty(t, u.Avatar)
+
+	u.Avatar = nil
+	require.NoError(t, u.Save(ctx, d))
+	count, err = db.Users().WhereEq("avatar", nil).Count(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
 func TestFindBySQL(t *testing.T) {
 	defer clear()
 
@@ -183,6 +1096,103 @@ func TestHasManyAssociationIsCached(t *testing.T) {
 	require.True(t, u.Posts().Loaded())
 }
 
+func TestPreload(t *testing.T) {
+	defer clear()
+
+	c := db.Categories().New()
+	c.Slug = "news"
+	require.NoError(t, c.Save(ctx, d))
+	for i := 0; i < 3; i++ {
+		u := createUser(t)
+		for j := 0; j < i; j++ {
+			p := u.Posts().New()
+			if j == 0 {
+				p.CategoryID = sql.NullString{String: "news", Valid: true}
+			}
+			require.NoError(t, p.Save(ctx, d))
+
+			tag := p.PostTags().New()
+			tag.Tag = "go"
+			require.NoError(t, tag.Save(ctx, d))
+		}
+	}
+
+	cd := &countingDB{DB: d}
+	users, err := db.Users().Preload("Posts.PostTags", "Posts.Category").Order("id").All(ctx, cd)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	require.Equal(t, 4, cd.queries)
+
+	for i, u := range users {
+		require.True(t, u.Posts().Loaded())
+		posts, err := u.Posts().All(ctx, cd)
+		require.NoError(t, err)
+		require.Len(t, posts, i)
+		for j, p := range posts {
+			require.True(t, p.PostTags().Loaded())
+			tags, err := p.PostTags().All(ctx, cd)
+			require.NoError(t, err)
+			require.Len(t, tags, 1)
+			require.Equal(t, "go", tags[0].Tag)
+
+			c, err := p.Category(ctx, cd)
+			require.NoError(t, err)
+			if p.CategoryID.Valid {
+				require.Equal(t, "news", c.Slug)
+			} else {
+				require.Nil(t, c)
+				require.NotZero(t, j)
+			}
+		}
+	}
+	require.Equal(t, 4, cd.queries)
+
+	cd.queries = 0
+	p, err := db.Posts().Preload("User").First(ctx, cd)
+	require.NoError(t, err)
+	u, err := p.User(ctx, cd)
+	require.NoError(t, err)
+	require.Equal(t, users[1].ID, u.ID)
+	require.Equal(t, 2, cd.queries)
+
+	_, err = db.Users().Preload("Posts.Comments").All(ctx, d)
+	require.EqualError(t, err, `posts: unknown association "Comments"`)
+
+	// The keys are deduplicated and split to fit in the bind parameters
+	defer func(dialect rel.Dialect) { db.Dialect = dialect }(db.Dialect)
+	db.Dialect = fewParams{rel.SQLite}
+	cd.queries = 0
+	posts, err := db.Posts().Preload("User").All(ctx, cd)
+	require.NoError(t, err)
+	require.Len(t, posts, 3)
+	require.Equal(t, 2, cd.queries)
+	for _, p := range posts {
+		u, err := p.User(ctx, cd)
+		require.NoError(t, err)
+		require.Equal(t, p.UserID, u.ID)
+	}
+
+	cd.queries = 0
+	users, err = db.Users().Preload("Posts").Order("id").All(ctx, cd)
+	require.NoError(t, err)
+	require.Equal(t, 3, cd.queries)
+	for i, u := range users {
+		posts, err := u.Posts().All(ctx, cd)
+		require.NoError(t, err)
+		require.Len(t, posts, i)
+	}
+	require.Equal(t, 3, cd.queries)
+}
+
+// fewParams is SQLite with room for two bind parameters per statement
+type fewParams struct {
+	rel.Dialect
+}
+
+func (fewParams) MaxParams() int {
+	return 2
+}
+
 func TestBelongsToAssociationIsCached(t *testing.T) {
 	defer clear()
 
@@ -201,6 +1211,95 @@ func TestBelongsToAssociationIsCached(t *testing.T) {
 	require.Equal(t, u2, u)
 }
 
+func TestStringPrimaryKey(t *testing.T) {
+	defer clear()
+
+	c := db.Categories().New()
+	c.Slug = "news"
+	c.Name = "News"
+	require.NoError(t, c.Save(ctx, d))
+
+	c, err := db.Categories().Find(ctx, d, "news")
+	require.NoError(t, err)
+	require.Equal(t, "News", c.Name)
+
+	c.Slug = "updates"
+	c.Name = "Updates"
+	require.NoError(t, c.Save(ctx, d))
+	_, err = db.Categories().Find(ctx, d, "news")
+	require.Equal(t, db.ErrNotFound, err)
+	c, err = db.Categories().First(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "updates", c.Slug)
+
+	u := createUser(t)
+	p := c.Posts().New()
+	require.Equal(t, "updates", p.CategoryID.String)
+	p.UserID = u.ID
+	require.NoError(t, p.Save(ctx, d))
+
+	c2, err := p.Category(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, c, c2)
+
+	p2, err := c.Posts().Find(ctx, d, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.ID, p2.ID)
+
+	p = u.Posts().New()
+	require.NoError(t, p.Save(ctx, d))
+	c2, err = p.Category(ctx, d)
+	require.NoError(t, err)
+	require.Nil(t, c2)
+
+	require.NoError(t, c.Delete(ctx, d))
+	n, err := db.Categories().Count(ctx, d)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestCompositePrimaryKey(t *testing.T) {
+	defer clear()
+
+	u := createUser(t)
+	p := u.Posts().New()
+	require.NoError(t, p.Save(ctx, d))
+
+	tag := p.PostTags().New()
+	tag.Tag = "go"
+	require.NoError(t, tag.Save(ctx, d))
+	other := p.PostTags().New()
+	other.Tag = "sql"
+	require.NoError(t, other.Save(ctx, d))
+
+	tag, err := db.PostTags().Find(ctx, d, p.ID, "go")
+	require.NoError(t, err)
+	require.Equal(t, "go", tag.Tag)
+
+	tag.Tag = "golang"
+	require.NoError(t, tag.Save(ctx, d))
+	_, err = p.PostTags().Find(ctx, d, p.ID, "go")
+	require.Equal(t, db.ErrNotFound, err)
+	tag, err = p.PostTags().Find(ctx, d, p.ID, "golang")
+	require.NoError(t, err)
+
+	first, err := p.PostTags().First(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "golang", first.Tag)
+	last, err := p.PostTags().Last(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, "sql", last.Tag)
+
+	post, err := tag.Post(ctx, d)
+	require.NoError(t, err)
+	require.Equal(t, p.ID, post.ID)
+
+	require.NoError(t, tag.Delete(ctx, d))
+	n, err := p.PostTags().Count(ctx, d)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+}
+
 func createUser(t *testing.T) *db.User {
 	u := db.Users().New()
 	err := u.Save(ctx, d)
@@ -1,11 +1,27 @@
 CREATE TABLE users (
   id INTEGER PRIMARY KEY,
   first_name TEXT NOT NULL,
-  last_name  TEXT NOT NULL
+  last_name  TEXT NOT NULL,
+  email TEXT UNIQUE,
+  avatar BLOB
+);
+
+CREATE TABLE categories (
+  slug TEXT PRIMARY KEY,
+  name TEXT NOT NULL
 );
 
 CREATE TABLE posts (
   id INTEGER PRIMARY KEY,
-  user_id INTEGER NOT NULL,
-  body TEXT NOT NULL
+  user_id INTEGER NOT NULL REFERENCES users (id),
+  category_id TEXT REFERENCES categories (slug),
+  body TEXT NOT NULL,
+  created_at DATETIME NOT NULL,
+  updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE post_tags (
+  post_id INTEGER NOT NULL REFERENCES posts (id),
+  tag TEXT NOT NULL,
+  PRIMARY KEY (post_id, tag)
 );
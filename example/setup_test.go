@@ -40,4 +40,27 @@ func TestMain(m *testing.M) {
 func clear() {
 	d.Exec("DELETE FROM users")
 	d.Exec("DELETE FROM posts")
+	d.Exec("DELETE FROM categories")
+	d.Exec("DELETE FROM post_tags")
+}
+
+// countingDB counts the queries run through it
+type countingDB struct {
+	*sql.DB
+	queries int
+}
+
+func (c *countingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.queries++
+	return c.DB.ExecContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.queries++
+	return c.DB.QueryContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.queries++
+	return c.DB.QueryRowContext(ctx, query, args...)
 }
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	_ "embed"
+	"flag"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed template.tmpl
+var defaultTemplate string
+
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	var source inputFlags
+	source.register(flags)
+	tmpl := flags.String("template", "", "path to a template `file` to use instead of the built-in one")
+	pkg := flags.String("pkg", "", "package `name` of the generated code; defaults to the schema's package, or $GOPACKAGE under go generate")
+	out := flags.String("out", "", "output `file`; writes to stdout when empty")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		return errors.Errorf("unexpected arguments %q", flags.Args())
+	}
+
+	input, err := source.load()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *pkg != "":
+		input.Package = *pkg
+	case input.Package == "":
+		// go generate sets $GOPACKAGE to the package containing the directive
+		input.Package = os.Getenv("GOPACKAGE")
+	}
+
+	tpl, err := loadTemplate(*tmpl)
+	if err != nil {
+		return err
+	}
+
+	output, err := Generate(tpl, input)
+	if output == nil {
+		return err
+	}
+
+	// Unformatted output is still written out so the broken code can be inspected
+	var werr error
+	if *out == "" {
+		_, werr = os.Stdout.Write(output)
+	} else {
+		werr = writeFileIfChanged(*out, output)
+	}
+	if err != nil {
+		return err
+	}
+	return werr
+}
+
+// inputFlags select where the schema is read from
+type inputFlags struct {
+	schema     string
+	sqlite     string
+	migrations string
+	skip       string
+}
+
+func (f *inputFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.schema, "schema", "", "path to the JSON or YAML schema `file`")
+	flags.StringVar(&f.sqlite, "sqlite", "", "path to a SQLite database `file` to reflect the schema from")
+	flags.StringVar(&f.migrations, "migrations", "", "path to a `directory` of SQL migrations to derive the schema from")
+	flags.StringVar(&f.skip, "skip", "schema_migrations", "comma-separated `tables` to leave out when reflecting a database")
+}
+
+func (f *inputFlags) load() (*Input, error) {
+	var sources []string
+	for _, path := range []string{f.schema, f.sqlite, f.migrations} {
+		if path != "" {
+			sources = append(sources, path)
+		}
+	}
+	if len(sources) > 1 {
+		return nil, errors.New("-schema, -sqlite and -migrations are mutually exclusive")
+	}
+
+	var tables Tables
+	var err error
+	switch {
+	case f.schema != "":
+		return LoadInput(f.schema)
+	case f.sqlite != "":
+		tables, err = loadSQLite(f.sqlite, strings.Split(f.skip, ","))
+	case f.migrations != "":
+		tables, err = LoadMigrations(f.migrations)
+	default:
+		return nil, errors.New("missing -schema, -sqlite or -migrations")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", sources[0])
+	}
+	return &Input{Tables: tables}, nil
+}
+
+func loadSQLite(path string, skip []string) (Tables, error) {
+	// Opening a missing file would create an empty database
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return ReflectSQLite(context.Background(), db, skip...)
+}
+
+func loadTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return template.New("template.tmpl").Parse(defaultTemplate)
+	}
+	return template.ParseFiles(path)
+}
+
+// Generate validates the input and renders it with tpl into formatted Go source.
+// When the rendered code doesn't parse, the unformatted source is returned with the error.
+func Generate(tpl *template.Template, input *Input) ([]byte, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	input.prepare()
+
+	var b bytes.Buffer
+	if err := tpl.Execute(&b, input); err != nil {
+		return nil, err
+	}
+
+	output, err := format.Source(b.Bytes())
+	if err != nil {
+		return b.Bytes(), errors.Wrap(err, "formatting generated code")
+	}
+	return output, nil
+}
+
+// writeFileIfChanged leaves the file untouched when it already has the given
+// contents, so build tools don't see a new modification time.
+func writeFileIfChanged(path string, data []byte) error {
+	if existing, err := ioutil.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
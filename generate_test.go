@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateCompiles builds the code generated for schemas that the
+// example doesn't cover
+func TestGenerateCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	tpl, err := loadTemplate("")
+	require.NoError(t, err)
+
+	for name, schema := range map[string]string{
+		// The only time column is a sql.NullTime
+		"nullable_time": `{"package": "db", "tables": [{"name": "events", "columns": [{"name": "id", "type": "int64"}, {"name": "seen_at", "type": "time.Time", "nullable": true}]}]}`,
+		// Nullable timestamps set by Save
+		"nullable_timestamps": `{"package": "db", "tables": [{"name": "events", "columns": [{"name": "id", "type": "int64"}, {"name": "created_at", "type": "time.Time", "nullable": true}, {"name": "updated_at", "type": "time.Time", "nullable": true}]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			input, err := ParseJSONInput([]byte(schema))
+			require.NoError(t, err)
+			output, err := Generate(tpl, input)
+			require.NoError(t, err)
+
+			// The package has to be in the module to import rel
+			dir, err := ioutil.TempDir(".", "generated")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "db.generated.go"), output, 0644))
+
+			out, err := exec.Command("go", "build", "./"+dir).CombinedOutput()
+			require.NoError(t, err, string(out))
+		})
+	}
+}
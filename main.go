@@ -1,65 +1,41 @@
 package main
 
 import (
-	"bytes"
-	"go/format"
+	"fmt"
 	"os"
-	"text/template"
 )
 
+const usage = `usage: orm <command> [flags]
+
+commands:
+  generate   generate a Go package from a schema document, database or migrations
+  reflect    write the schema document for a database or migrations
+
+Run "orm <command> -h" for the flags of a command.
+`
+
 func main() {
-	tpl := template.Must(template.ParseFiles("template.tmpl"))
-	tables := Tables{
-		{
-			Name: "users",
-			Columns: Columns{
-				{
-					Name: "id",
-					Type: "int64",
-				},
-				{
-					Name: "first_name",
-					Type: "string",
-				},
-				{
-					Name: "last_name",
-					Type: "string",
-				},
-			},
-			HasMany: []TableName{"posts"},
-		},
-		{
-			Name: "posts",
-			Columns: Columns{
-				{
-					Name: "id",
-					Type: "int64",
-				},
-				{
-					Name: "user_id",
-					Type: "int64",
-				},
-				{
-					Name: "body",
-					Type: "string",
-				},
-			},
-			BelongsTo: []TableName{"users"},
-		},
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
 
-	var b bytes.Buffer
-	err := tpl.Execute(&b, Input{
-		Tables:  tables,
-		Package: "db",
-	})
-	if err != nil {
-		panic(err)
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "reflect":
+		err = runReflect(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "orm: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
 	}
-	output, err := format.Source(b.Bytes())
+
 	if err != nil {
-		b.WriteTo(os.Stdout)
-		panic(err)
+		fmt.Fprintf(os.Stderr, "orm %s: %v\n", os.Args[1], err)
+		os.Exit(1)
 	}
-	os.Stdout.Write(output)
 }
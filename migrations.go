@@ -0,0 +1,471 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoadMigrations derives the tables from a directory of migrations by
+// applying them in name order. Both <version>_<name>/up.sql directories and
+// <version>_<name>.up.sql files are picked up.
+func LoadMigrations(dir string) (Tables, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var s migrationSchema
+	for _, entry := range entries {
+		var path string
+		switch {
+		case entry.IsDir():
+			path = filepath.Join(dir, entry.Name(), "up.sql")
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue
+			}
+		case strings.HasSuffix(entry.Name(), ".up.sql"):
+			path = filepath.Join(dir, entry.Name())
+		default:
+			continue
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.apply(string(src)); err != nil {
+			return nil, errors.Wrapf(err, "%s", path)
+		}
+	}
+
+	return buildTables(s.tables)
+}
+
+// migrationSchema is the state of the schema after applying migrations
+type migrationSchema struct {
+	tables []sqlTable
+}
+
+func (s *migrationSchema) table(name string) *sqlTable {
+	for i := range s.tables {
+		if s.tables[i].Name == name {
+			return &s.tables[i]
+		}
+	}
+	return nil
+}
+
+func (s *migrationSchema) apply(src string) error {
+	tokens, err := tokenizeSQL(src)
+	if err != nil {
+		return err
+	}
+
+	p := &sqlParser{tokens: tokens}
+	for !p.done() {
+		if p.accept(";") {
+			continue
+		}
+		line := p.peek().line
+		if err := s.statement(p); err != nil {
+			return errors.Wrapf(err, "line %d", line)
+		}
+	}
+	return nil
+}
+
+func (s *migrationSchema) statement(p *sqlParser) error {
+	switch {
+	case p.accept("CREATE"):
+		p.accept("TEMP", "TEMPORARY")
+		unique := p.accept("UNIQUE")
+		switch {
+		case p.accept("TABLE"):
+			return s.createTable(p)
+		case p.accept("INDEX"):
+			return s.createIndex(p)
+		case unique:
+			return p.errorf("expected INDEX")
+		case p.accept("TRIGGER"):
+			// The body holds statements of its own
+			for !p.done() && !p.accept("END") {
+				p.next()
+			}
+		}
+	case p.accept("ALTER"):
+		if err := p.expect("TABLE"); err != nil {
+			return err
+		}
+		return s.alterTable(p)
+	case p.accept("DROP"):
+		if p.accept("TABLE") {
+			return s.dropTable(p)
+		}
+	}
+
+	// Statements that don't change the tables are skipped
+	p.skipStatement()
+	return nil
+}
+
+func (s *migrationSchema) createTable(p *sqlParser) error {
+	exists := p.accept("IF")
+	if exists {
+		if err := p.expect("NOT"); err != nil {
+			return err
+		}
+		if err := p.expect("EXISTS"); err != nil {
+			return err
+		}
+	}
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	if s.table(name) != nil {
+		if exists {
+			p.skipStatement()
+			return nil
+		}
+		return errors.Errorf("table %q already exists", name)
+	}
+
+	if !p.accept("(") {
+		return errors.Errorf("table %q: only CREATE TABLE with column definitions is supported", name)
+	}
+
+	t := sqlTable{Name: name}
+	for {
+		if err := parseTableElement(p, &t); err != nil {
+			return errors.Wrapf(err, "table %q", name)
+		}
+		if p.accept(")") {
+			break
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+	}
+	p.skipStatement()
+
+	s.tables = append(s.tables, t)
+	return nil
+}
+
+func (s *migrationSchema) alterTable(p *sqlParser) error {
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	t := s.table(name)
+	if t == nil {
+		return errors.Errorf("unknown table %q", name)
+	}
+
+	switch {
+	case p.accept("ADD"):
+		// Without COLUMN, it can also be a table constraint
+		add := parseTableElement
+		if p.accept("COLUMN") {
+			add = parseColumn
+		}
+		if err := add(p, t); err != nil {
+			return errors.Wrapf(err, "table %q", name)
+		}
+	case p.accept("DROP"):
+		p.accept("COLUMN")
+		column, err := p.name()
+		if err != nil {
+			return err
+		}
+		if !t.dropColumn(column) {
+			return errors.Errorf("table %q: unknown column %q", name, column)
+		}
+	case p.accept("RENAME"):
+		if p.accept("TO") {
+			newName, err := p.name()
+			if err != nil {
+				return err
+			}
+			s.renameTable(t, newName)
+			break
+		}
+		p.accept("COLUMN")
+		column, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		newName, err := p.name()
+		if err != nil {
+			return err
+		}
+		if !t.renameColumn(column, newName) {
+			return errors.Errorf("table %q: unknown column %q", name, column)
+		}
+	default:
+		return p.errorf("unsupported ALTER TABLE")
+	}
+
+	p.skipStatement()
+	return nil
+}
+
+func (s *migrationSchema) dropTable(p *sqlParser) error {
+	exists := p.accept("IF")
+	if exists {
+		if err := p.expect("EXISTS"); err != nil {
+			return err
+		}
+	}
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	p.skipStatement()
+
+	for i := range s.tables {
+		if s.tables[i].Name == name {
+			s.tables = append(s.tables[:i], s.tables[i+1:]...)
+			return nil
+		}
+	}
+	if exists {
+		return nil
+	}
+	return errors.Errorf("unknown table %q", name)
+}
+
+// createIndex only checks the indexed table, as indexes don't show up in the generated code
+func (s *migrationSchema) createIndex(p *sqlParser) error {
+	p.accept("CONCURRENTLY")
+	if p.accept("IF") {
+		if err := p.expect("NOT"); err != nil {
+			return err
+		}
+		if err := p.expect("EXISTS"); err != nil {
+			return err
+		}
+	}
+	// PostgreSQL names the index itself if it isn't given a name
+	if !p.accept("ON") {
+		if _, err := p.qualifiedName(); err != nil {
+			return err
+		}
+		if err := p.expect("ON"); err != nil {
+			return err
+		}
+	}
+	p.accept("ONLY")
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if s.table(name) == nil {
+		return errors.Errorf("index on unknown table %q", name)
+	}
+
+	p.skipStatement()
+	return nil
+}
+
+func (s *migrationSchema) renameTable(t *sqlTable, name string) {
+	for i := range s.tables {
+		for j := range s.tables[i].ForeignKeys {
+			if s.tables[i].ForeignKeys[j].Table == t.Name {
+				s.tables[i].ForeignKeys[j].Table = name
+			}
+		}
+	}
+	t.Name = name
+}
+
+func (t *sqlTable) column(name string) *sqlColumn {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+func (t *sqlTable) dropColumn(name string) bool {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+			fks := t.ForeignKeys[:0]
+			for _, fk := range t.ForeignKeys {
+				if !strings.EqualFold(fk.Column, name) {
+					fks = append(fks, fk)
+				}
+			}
+			t.ForeignKeys = fks
+			return true
+		}
+	}
+	return false
+}
+
+func (t *sqlTable) renameColumn(name, newName string) bool {
+	c := t.column(name)
+	if c == nil {
+		return false
+	}
+	c.Name = newName
+	for i := range t.ForeignKeys {
+		if strings.EqualFold(t.ForeignKeys[i].Column, name) {
+			t.ForeignKeys[i].Column = newName
+		}
+	}
+	return true
+}
+
+// parseTableElement parses a column definition or a table constraint
+func parseTableElement(p *sqlParser, t *sqlTable) error {
+	if p.accept("CONSTRAINT") {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.accept("PRIMARY"):
+		if err := p.expect("KEY"); err != nil {
+			return err
+		}
+		columns, err := p.nameList()
+		if err != nil {
+			return err
+		}
+		for i, name := range columns {
+			c := t.column(name)
+			if c == nil {
+				return errors.Errorf("primary key on unknown column %q", name)
+			}
+			c.PrimaryKey = i + 1
+		}
+	case p.accept("FOREIGN"):
+		if err := p.expect("KEY"); err != nil {
+			return err
+		}
+		columns, err := p.nameList()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("REFERENCES"); err != nil {
+			return err
+		}
+		fk, err := parseReferences(p)
+		if err != nil {
+			return err
+		}
+		// Only single column foreign keys can become associations
+		if len(columns) == 1 {
+			fk.Column = columns[0]
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		}
+	case p.accept("UNIQUE"), p.accept("CHECK"), p.accept("EXCLUDE"):
+		p.skipElement()
+	default:
+		return parseColumn(p, t)
+	}
+
+	p.skipElement()
+	return nil
+}
+
+func parseColumn(p *sqlParser, t *sqlTable) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if t.column(name) != nil {
+		return errors.Errorf("column %q already exists", name)
+	}
+	col := sqlColumn{Name: name}
+
+	// The type is every word up to the first constraint, with an optional size
+	var words []string
+	for !p.done() && p.peek().isWord() && !columnConstraints[strings.ToUpper(p.peek().text)] {
+		words = append(words, p.next().text)
+	}
+	if p.peek().text == "(" {
+		p.skipParens()
+	}
+	col.Type = strings.Join(words, " ")
+
+	for !p.done() && p.peek().text != "," && p.peek().text != ")" && p.peek().text != ";" {
+		switch {
+		case p.accept("PRIMARY"):
+			if err := p.expect("KEY"); err != nil {
+				return errors.Wrapf(err, "column %q", name)
+			}
+			col.PrimaryKey = 1
+		case p.accept("NOT"):
+			// Also NOT DEFERRABLE in foreign key clauses
+			if p.accept("NULL") {
+				col.NotNull = true
+			}
+		case p.accept("REFERENCES"):
+			fk, err := parseReferences(p)
+			if err != nil {
+				return errors.Wrapf(err, "column %q", name)
+			}
+			fk.Column = name
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		case p.peek().text == "(":
+			p.skipParens()
+		default:
+			p.next()
+		}
+	}
+
+	t.Columns = append(t.Columns, col)
+	return nil
+}
+
+// parseReferences parses the part of a foreign key clause after REFERENCES
+func parseReferences(p *sqlParser) (sqlForeignKey, error) {
+	var fk sqlForeignKey
+	var err error
+	fk.Table, err = p.qualifiedName()
+	if err != nil {
+		return fk, err
+	}
+	if p.peek().text == "(" {
+		columns, err := p.nameList()
+		if err != nil {
+			return fk, err
+		}
+		if len(columns) == 1 {
+			fk.RefColumn = columns[0]
+		}
+	}
+	return fk, nil
+}
+
+// columnConstraints are the keywords that end the type of a column definition
+var columnConstraints = map[string]bool{
+	"CONSTRAINT":     true,
+	"PRIMARY":        true,
+	"NOT":            true,
+	"NULL":           true,
+	"UNIQUE":         true,
+	"CHECK":          true,
+	"DEFAULT":        true,
+	"COLLATE":        true,
+	"REFERENCES":     true,
+	"GENERATED":      true,
+	"AS":             true,
+	"AUTOINCREMENT":  true,
+	"AUTO_INCREMENT": true,
+}
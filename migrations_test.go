@@ -0,0 +1,137 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrationsExample(t *testing.T) {
+	tables, err := LoadMigrations("example/migrations")
+	require.NoError(t, err)
+
+	input, err := LoadInput("example/schema.json")
+	require.NoError(t, err)
+	// Validations and touch_on_update_all can't be expressed in SQL
+	for n := range input.Tables {
+		table := &input.Tables[n]
+		table.TouchOnUpdateAll = false
+		for i := range table.Columns {
+			table.Columns[i].Validations = nil
+		}
+	}
+	require.Equal(t, input.Tables, tables)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, src := range map[string]string{
+		"001_users.up.sql": `
+			-- accounts, later renamed
+			CREATE TABLE IF NOT EXISTS "accounts" (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL DEFAULT 'nobody',
+				bio TEXT,
+				admin BOOLEAN NOT NULL CHECK (admin IN (0, 1)),
+				avatar BYTEA,
+				created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT (CURRENT_TIMESTAMP)
+			);
+			CREATE UNIQUE INDEX accounts_name ON accounts (name);`,
+		"001_users.down.sql": `DROP TABLE accounts;`,
+		"002_posts.up.sql": `
+			CREATE TABLE posts (
+				id INTEGER NOT NULL,
+				user_id INTEGER NOT NULL,
+				editor_id INTEGER REFERENCES accounts (id) ON DELETE SET NULL,
+				/* dropped below */
+				legacy TEXT,
+				PRIMARY KEY (id),
+				CONSTRAINT posts_user FOREIGN KEY (user_id) REFERENCES accounts (id) NOT DEFERRABLE
+			);
+			CREATE TABLE scratch (id INTEGER PRIMARY KEY);`,
+		"003_changes.up.sql": `
+			ALTER TABLE accounts RENAME TO users;
+			ALTER TABLE posts ADD COLUMN score REAL;
+			ALTER TABLE posts DROP COLUMN legacy;
+			ALTER TABLE posts RENAME COLUMN editor_id TO reviewer_id;
+			DROP TABLE IF EXISTS scratch;
+			CREATE TABLE comments (id INTEGER PRIMARY KEY, post_id INTEGER NOT NULL);
+			ALTER TABLE comments ADD CONSTRAINT comments_post FOREIGN KEY (post_id) REFERENCES posts (id);
+			ALTER TABLE comments ADD UNIQUE (post_id, id);
+			ALTER TABLE comments ADD CHECK (id > 0);
+			CREATE INDEX CONCURRENTLY comments_post ON comments (post_id);
+			CREATE INDEX ON ONLY comments (post_id);
+			INSERT INTO users (name, admin) VALUES ('root; admin', 1);`,
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644))
+	}
+
+	tables, err := LoadMigrations(dir)
+	require.NoError(t, err)
+	require.Equal(t, Tables{
+		{
+			Name: "users",
+			Columns: []Column{
+				{Name: "id", Type: "int64"},
+				{Name: "name", Type: "string"},
+				{Name: "bio", Type: "string", Nullable: true},
+				{Name: "admin", Type: "bool"},
+				{Name: "avatar", Type: "[]byte", Nullable: true},
+				{Name: "created_at", Type: "time.Time"},
+			},
+			HasMany: []TableName{"posts"},
+		},
+		{
+			Name: "posts",
+			Columns: []Column{
+				{Name: "id", Type: "int64"},
+				{Name: "user_id", Type: "int64"},
+				{Name: "reviewer_id", Type: "int64", Nullable: true},
+				{Name: "score", Type: "float64", Nullable: true},
+			},
+			BelongsTo: []TableName{"users"},
+			HasMany:   []TableName{"comments"},
+		},
+		{
+			Name: "comments",
+			Columns: []Column{
+				{Name: "id", Type: "int64"},
+				{Name: "post_id", Type: "int64"},
+			},
+			BelongsTo: []TableName{"posts"},
+		},
+	}, tables)
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	for _, c := range []struct {
+		src string
+		err string
+	}{
+		{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY);\nALTER TABLE posts ADD COLUMN body TEXT;",
+			`line 2: unknown table "posts"`,
+		},
+		{
+			"CREATE TABLE users (\n  id INTEGER PRIMARY KEY,\n  id TEXT\n);",
+			`line 1: table "users": column "id" already exists`,
+		},
+		{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY 'oops);",
+			`line 1: unterminated '`,
+		},
+		{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY);\nCREATE INDEX users_name ON people (name);",
+			`line 2: index on unknown table "people"`,
+		},
+	} {
+		var s migrationSchema
+		require.EqualError(t, s.apply(c.src), c.err)
+	}
+}
@@ -1,12 +1,113 @@
 package main // import "bou.ke/orm"
 
 import (
+	"encoding/json"
+	"go/token"
+	"sort"
+	"strings"
+
 	"github.com/gobuffalo/flect"
+	"github.com/pkg/errors"
 )
 
 type Input struct {
-	Tables  Tables
-	Package string
+	Tables  Tables `json:"tables" yaml:"tables"`
+	Package string `json:"package" yaml:"package"`
+
+	// NullStyle selects how nullable columns are represented: "sql" for the
+	// sql.Null* types (the default) or "pointer" for pointer fields
+	NullStyle string `json:"null_style,omitempty" yaml:"null_style,omitempty"`
+
+	// Dialect is the database the generated code builds queries for by
+	// default: "sqlite" (the default), "sqlite_returning" for SQLite 3.35.0
+	// or later, "postgres" or "mysql"
+	Dialect string `json:"dialect,omitempty" yaml:"dialect,omitempty"`
+}
+
+const (
+	NullStyleSQL     = "sql"
+	NullStylePointer = "pointer"
+)
+
+// dialects maps the dialect names onto the variables in the rel package
+var dialects = map[string]string{
+	"sqlite":           "SQLite",
+	"sqlite_returning": "SQLiteReturning",
+	"postgres":         "Postgres",
+	"mysql":            "MySQL",
+}
+
+// DialectVar is the rel package variable holding the input's dialect
+func (i *Input) DialectVar() string {
+	if i.Dialect == "" {
+		return dialects["sqlite"]
+	}
+	return dialects[i.Dialect]
+}
+
+// prepare copies project-wide settings onto the columns, so the template can use them
+func (i *Input) prepare() {
+	for t := range i.Tables {
+		for c := range i.Tables[t].Columns {
+			i.Tables[t].Columns[c].pointerNull = i.NullStyle == NullStylePointer
+		}
+	}
+}
+
+// Imports lists the packages needed by the column types, besides the ones the template always imports
+func (i *Input) Imports() []string {
+	imports := map[string]bool{}
+	for _, t := range i.Tables {
+		for _, c := range t.Columns {
+			// Also for sql.NullTime, as the generated time helpers use the package
+			if strings.Contains(c.Type, "time.") {
+				imports["time"] = true
+			}
+			if c.IsBytes() {
+				imports["bytes"] = true
+			}
+			for _, imp := range c.validationImports() {
+				imports[imp] = true
+			}
+		}
+	}
+
+	var list []string
+	for imp := range imports {
+		list = append(list, imp)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// HasTimestamps is true when any table has timestamp columns
+func (i *Input) HasTimestamps() bool {
+	for n := range i.Tables {
+		if i.Tables[n].HasTimestamps() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTimeColumns is true when any column holds a time.Time
+func (i *Input) HasTimeColumns() bool {
+	for n := range i.Tables {
+		if i.Tables[n].HasTimeColumns() {
+			return true
+		}
+	}
+	return false
+}
+
+// Table returns the table with the given name, or nil if there is none
+func (i *Input) Table(name TableName) *Table {
+	for n := range i.Tables {
+		if i.Tables[n].Name == string(name) {
+			return &i.Tables[n]
+		}
+	}
+	return nil
 }
 
 type TableName string
@@ -25,10 +126,62 @@ func (t TableName) RelationName() string {
 
 type Tables []Table
 type Table struct {
-	Name      string      `json:"name"`
-	Columns   []Column    `json:"columns"`
-	BelongsTo []TableName `json:"belongs_to"`
-	HasMany   []TableName `json:"has_many"`
+	Name      string      `json:"name" yaml:"name"`
+	Columns   []Column    `json:"columns" yaml:"columns"`
+	BelongsTo []TableName `json:"belongs_to,omitempty" yaml:"belongs_to,omitempty"`
+	HasMany   []TableName `json:"has_many,omitempty" yaml:"has_many,omitempty"`
+
+	// PrimaryKey names the primary key columns, "id" if empty
+	PrimaryKey PrimaryKey `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+
+	// TouchOnUpdateAll makes UpdateAll set the update timestamps too
+	TouchOnUpdateAll bool `json:"touch_on_update_all,omitempty" yaml:"touch_on_update_all,omitempty"`
+}
+
+// PrimaryKey lists the columns of a primary key. In a schema document it's
+// either a single column name or a list of them.
+type PrimaryKey []string
+
+func (k *PrimaryKey) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*k = PrimaryKey{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return errors.New("primary_key must be a column name or a list of column names")
+	}
+	*k = names
+	return nil
+}
+
+func (k PrimaryKey) MarshalJSON() ([]byte, error) {
+	if len(k) == 1 {
+		return json.Marshal(k[0])
+	}
+	return json.Marshal([]string(k))
+}
+
+func (k *PrimaryKey) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*k = PrimaryKey{name}
+		return nil
+	}
+	var names []string
+	if err := unmarshal(&names); err != nil {
+		return errors.New("primary_key must be a column name or a list of column names")
+	}
+	*k = names
+	return nil
+}
+
+func (k PrimaryKey) MarshalYAML() (interface{}, error) {
+	if len(k) == 1 {
+		return k[0], nil
+	}
+	return []string(k), nil
 }
 
 func (t *Table) Singular() string {
@@ -43,12 +196,232 @@ func (t *Table) RelationName() string {
 	return flect.Pascalize(t.Name)
 }
 
+// PrimaryKeyNames returns the names of the primary key columns
+func (t *Table) PrimaryKeyNames() []string {
+	if len(t.PrimaryKey) == 0 {
+		return []string{"id"}
+	}
+	return t.PrimaryKey
+}
+
+// PrimaryKeyColumns returns the primary key columns, with nil for the ones that don't exist
+func (t *Table) PrimaryKeyColumns() []*Column {
+	names := t.PrimaryKeyNames()
+	columns := make([]*Column, len(names))
+	for i, name := range names {
+		columns[i] = t.Column(name)
+	}
+	return columns
+}
+
+// PrimaryKeyColumn returns the primary key column, or nil if the primary key is composite
+func (t *Table) PrimaryKeyColumn() *Column {
+	if columns := t.PrimaryKeyColumns(); len(columns) == 1 {
+		return columns[0]
+	}
+	return nil
+}
+
+// AutoIncrementColumn returns the primary key column if the database assigns
+// it when the application leaves it zero, which is assumed for integer keys.
+func (t *Table) AutoIncrementColumn() *Column {
+	pk := t.PrimaryKeyColumn()
+	if pk == nil {
+		return nil
+	}
+	switch pk.Type {
+	case "int", "int32", "int64":
+		return pk
+	default:
+		return nil
+	}
+}
+
+// HasTimeColumns is true when any of the table's columns holds a time.Time
+func (t *Table) HasTimeColumns() bool {
+	for i := range t.Columns {
+		if t.Columns[i].Type == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTimestamps is true when the table has timestamp columns
+func (t *Table) HasTimestamps() bool {
+	for i := range t.Columns {
+		if t.Columns[i].SetOnCreate() {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateTimestampColumns returns the columns Save sets to the current time on update
+func (t *Table) UpdateTimestampColumns() []*Column {
+	var columns []*Column
+	for i := range t.Columns {
+		if t.Columns[i].SetOnUpdate() {
+			columns = append(columns, &t.Columns[i])
+		}
+	}
+	return columns
+}
+
+// UpsertColumns returns the columns an upsert updates in conflicting rows:
+// all but the primary key and the timestamps that are only set on create
+func (t *Table) UpsertColumns() []*Column {
+	var columns []*Column
+	for i := range t.Columns {
+		c := &t.Columns[i]
+		if c.SetOnCreate() && !c.SetOnUpdate() {
+			continue
+		}
+		primary := false
+		for _, name := range t.PrimaryKeyNames() {
+			primary = primary || name == c.Name
+		}
+		if !primary {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// ForeignKey returns the column referencing the other table in a belongs_to association
+func (t *Table) ForeignKey(other TableName) *Column {
+	return t.Column(other.Singular() + "_id")
+}
+
+// Column returns the column with the given name, or nil if there is none
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
 type Columns []Column
 type Column struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the Go type of the column's values, without regard to NULL
+	Type     string `json:"type" yaml:"type"`
+	Nullable bool   `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+
+	Validations *Validations `json:"validations,omitempty" yaml:"validations,omitempty"`
+
+	// Timestamp is "create" for a time the generated Save sets when it
+	// inserts the record, "update" for one it also sets whenever it updates
+	// it, or "none". Columns named created_at and updated_at are timestamps
+	// by default.
+	Timestamp string `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+
+	pointerNull bool
+}
+
+const (
+	TimestampCreate = "create"
+	TimestampUpdate = "update"
+	TimestampNone   = "none"
+)
+
+// timestamp is the kind of timestamp the column is, "" if it's none
+func (c *Column) timestamp() string {
+	switch {
+	case c.Timestamp == TimestampNone:
+		return ""
+	case c.Timestamp != "":
+		return c.Timestamp
+	case c.Type != "time.Time":
+		return ""
+	case c.Name == "created_at":
+		return TimestampCreate
+	case c.Name == "updated_at":
+		return TimestampUpdate
+	default:
+		return ""
+	}
+}
+
+// SetOnCreate is true when Save sets the column to the current time on insert
+func (c *Column) SetOnCreate() bool {
+	return c.timestamp() != ""
+}
+
+// SetOnUpdate is true when Save sets the column to the current time on update
+func (c *Column) SetOnUpdate() bool {
+	return c.timestamp() == TimestampUpdate
 }
 
 func (c *Column) FieldName() string {
 	return flect.Pascalize(c.Name)
 }
+
+// ParamName is the name of a function parameter holding a value for the column
+func (c *Column) ParamName() string {
+	name := flect.Camelize(c.Name)
+	if name == strings.ToUpper(name) {
+		// Single initialisms like "id" come out as "ID"
+		name = strings.ToLower(name)
+	}
+	switch {
+	case token.IsKeyword(name), name == "ctx", name == "db", name == "o", name == "q":
+		return name + "Value"
+	default:
+		return name
+	}
+}
+
+// GoType is the type of the column's struct field
+func (c *Column) GoType() string {
+	switch {
+	case c.IsNullStruct():
+		return c.NullType()
+	case c.IsPointer():
+		return "*" + c.Type
+	default:
+		return c.Type
+	}
+}
+
+// IsBytes is true for []byte columns, which need bytes.Equal to compare and use nil for NULL
+func (c *Column) IsBytes() bool {
+	return c.Type == "[]byte"
+}
+
+// IsNullStruct is true when the field is one of the sql.Null* types
+func (c *Column) IsNullStruct() bool {
+	return c.Nullable && !c.pointerNull && !c.IsBytes()
+}
+
+// IsPointer is true when the field is a pointer that is nil for NULL
+func (c *Column) IsPointer() bool {
+	return c.Nullable && c.pointerNull && !c.IsBytes()
+}
+
+var nullTypes = map[string]string{
+	"bool":      "Bool",
+	"byte":      "Byte",
+	"float64":   "Float64",
+	"int16":     "Int16",
+	"int32":     "Int32",
+	"int64":     "Int64",
+	"string":    "String",
+	"time.Time": "Time",
+}
+
+// NullType is the sql.Null* type for the column's type, or "" if there is none
+func (c *Column) NullType() string {
+	if name, ok := nullTypes[c.Type]; ok {
+		return "sql.Null" + name
+	}
+	return ""
+}
+
+// NullValueField is the name of the field holding the value in the column's sql.Null* type
+func (c *Column) NullValueField() string {
+	return nullTypes[c.Type]
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+func runReflect(args []string) error {
+	flags := flag.NewFlagSet("reflect", flag.ExitOnError)
+	var source inputFlags
+	source.register(flags)
+	pkg := flags.String("pkg", "", "package `name` to put in the schema document")
+	format := flags.String("format", "json", "`format` of the schema document, json or yaml")
+	out := flags.String("out", "", "output `file`; writes to stdout when empty")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		return errors.Errorf("unexpected arguments %q", flags.Args())
+	}
+
+	input, err := source.load()
+	if err != nil {
+		return err
+	}
+	if *pkg != "" {
+		input.Package = *pkg
+	}
+
+	var output []byte
+	switch *format {
+	case "json":
+		output, err = json.MarshalIndent(input, "", "  ")
+		output = append(output, '\n')
+	case "yaml":
+		output, err = yaml.Marshal(input)
+	default:
+		return errors.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(output)
+		return err
+	}
+	return writeFileIfChanged(*out, output)
+}
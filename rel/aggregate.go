@@ -0,0 +1,23 @@
+package rel
+
+// Aggregate is an aggregate function like COUNT, SUM or MAX, whose name is
+// written as is. A nil Expr counts the rows, as in COUNT(*).
+type Aggregate struct {
+	Func     string
+	Expr     Expr
+	Distinct bool
+}
+
+func (a Aggregate) writeTo(c *collector) {
+	c.WriteString(a.Func)
+	c.WriteString("(")
+	if a.Distinct {
+		c.WriteString("DISTINCT ")
+	}
+	if a.Expr == nil {
+		c.WriteString("*")
+	} else {
+		a.Expr.writeTo(c)
+	}
+	c.WriteString(")")
+}
@@ -0,0 +1,25 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	stmt := SelectStatement{
+		Table: "posts",
+		Columns: []Expr{
+			Field{"user_id"},
+			Aggregate{Func: "COUNT"},
+			Aggregate{Func: "COUNT", Expr: Field{"category_id"}, Distinct: true},
+		},
+		Wheres:  []Expr{IsNotNull{Field{"category_id"}}},
+		Groups:  []Expr{Field{"user_id"}},
+		Havings: []Expr{GreaterThan{Field: Field{"user_id"}, Value: BindParam{1}}, Literal{Text: "COUNT(*) > ?", Params: []interface{}{2}}},
+		Orders:  []Expr{Descending{Aggregate{Func: "MAX", Expr: Field{"id"}}}},
+	}
+	query, values := stmt.Build()
+	require.Equal(t, `SELECT "user_id", COUNT(*), COUNT(DISTINCT "category_id") FROM "posts" WHERE "category_id" IS NOT NULL GROUP BY "user_id" HAVING "user_id" > ? AND (COUNT(*) > ?) ORDER BY MAX("id") DESC`, query)
+	require.Equal(t, []interface{}{1, 2}, values)
+}
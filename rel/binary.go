@@ -1,5 +1,11 @@
 package rel
 
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
 type Assignment struct {
 	Field Field
 	Value Expr
@@ -11,6 +17,8 @@ func (a Assignment) writeTo(c *collector) {
 	a.Value.writeTo(c)
 }
 
+// Equality compares the field with the value. A nil Value, or a BindParam
+// holding a value that's stored as NULL, becomes IS NULL.
 type Equality struct {
 	Field Field
 	Value Expr
@@ -18,7 +26,7 @@ type Equality struct {
 
 func (a Equality) writeTo(c *collector) {
 	a.Field.writeTo(c)
-	if a.Value == nil {
+	if a.Value == nil || isNullParam(a.Value) {
 		c.WriteString(" IS NULL")
 	} else {
 		c.WriteString(" = ")
@@ -26,12 +34,130 @@ func (a Equality) writeTo(c *collector) {
 	}
 }
 
+// NotEqual is the opposite of Equality, becoming IS NOT NULL for NULL values
+type NotEqual struct {
+	Field Field
+	Value Expr
+}
+
+func (n NotEqual) writeTo(c *collector) {
+	if n.Value == nil || isNullParam(n.Value) {
+		IsNotNull{n.Field}.writeTo(c)
+		return
+	}
+	writeComparison(c, n.Field, " <> ", n.Value)
+}
+
+// IsNotNull matches values that aren't NULL. Use an Equality with a nil
+// Value for the opposite.
+type IsNotNull struct {
+	Field Field
+}
+
+func (i IsNotNull) writeTo(c *collector) {
+	i.Field.writeTo(c)
+	c.WriteString(" IS NOT NULL")
+}
+
+type LessThan struct {
+	Field Field
+	Value Expr
+}
+
+func (l LessThan) writeTo(c *collector) {
+	writeComparison(c, l.Field, " < ", l.Value)
+}
+
+type LessThanOrEqual struct {
+	Field Field
+	Value Expr
+}
+
+func (l LessThanOrEqual) writeTo(c *collector) {
+	writeComparison(c, l.Field, " <= ", l.Value)
+}
+
+type GreaterThan struct {
+	Field Field
+	Value Expr
+}
+
+func (g GreaterThan) writeTo(c *collector) {
+	writeComparison(c, g.Field, " > ", g.Value)
+}
+
+type GreaterThanOrEqual struct {
+	Field Field
+	Value Expr
+}
+
+func (g GreaterThanOrEqual) writeTo(c *collector) {
+	writeComparison(c, g.Field, " >= ", g.Value)
+}
+
+func writeComparison(c *collector, field Field, op string, value Expr) {
+	field.writeTo(c)
+	c.WriteString(op)
+	value.writeTo(c)
+}
+
+// Between matches values from Low up to and including High
+type Between struct {
+	Field Field
+	Low   Expr
+	High  Expr
+}
+
+func (b Between) writeTo(c *collector) {
+	b.Field.writeTo(c)
+	c.WriteString(" BETWEEN ")
+	b.Low.writeTo(c)
+	c.WriteString(" AND ")
+	b.High.writeTo(c)
+}
+
+// Like matches the field against a pattern, in which % matches any text and
+// _ a single character. ! is the escape character, so use EscapeLike for
+// text that has to match literally. A CaseInsensitive match uses ILIKE where
+// the dialect has it, and compares lowercased values otherwise. Whether a
+// plain LIKE is case-sensitive depends on the database.
+type Like struct {
+	Field           Field
+	Pattern         Expr
+	CaseInsensitive bool
+}
+
+func (l Like) writeTo(c *collector) {
+	switch op := c.dialect.CaseInsensitiveLike(); {
+	case !l.CaseInsensitive:
+		writeComparison(c, l.Field, " LIKE ", l.Pattern)
+	case op != "":
+		writeComparison(c, l.Field, " "+op+" ", l.Pattern)
+	default:
+		c.WriteString("LOWER(")
+		l.Field.writeTo(c)
+		c.WriteString(") LIKE LOWER(")
+		l.Pattern.writeTo(c)
+		c.WriteString(")")
+	}
+	c.WriteString(" ESCAPE '!'")
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// EscapeLike escapes the wildcards in text, for use in a Like pattern
+func EscapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+// Field is a column, optionally qualified with its table like
+// "users.id". The name is quoted, so any column name can be used.
 type Field struct {
 	Name string
 }
 
 func (f Field) writeTo(c *collector) {
-	c.WriteString(f.Name)
+	c.writeIdentifier(f.Name)
 }
 
 // In is an SQL IN expression
@@ -50,3 +176,50 @@ func (i In) writeTo(c *collector) {
 	i.Right.writeTo(c)
 	c.WriteString(")")
 }
+
+// NotIn is an SQL NOT IN expression, which matches everything for an empty list
+type NotIn struct {
+	Left  Expr
+	Right ExprList
+}
+
+func (n NotIn) writeTo(c *collector) {
+	if len(n.Right) == 0 {
+		c.WriteString("1=1")
+		return
+	}
+	n.Left.writeTo(c)
+	c.WriteString(" NOT IN (")
+	n.Right.writeTo(c)
+	c.WriteString(")")
+}
+
+func isNullParam(e Expr) bool {
+	switch b := e.(type) {
+	case BindParam:
+		return IsNull(b.Value)
+	case *BindParam:
+		return IsNull(b.Value)
+	default:
+		return false
+	}
+}
+
+// IsNull reports whether the value is stored as NULL: nil, a nil pointer or
+// slice, or a driver.Valuer like sql.NullString that gives nil.
+func IsNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		if v.IsNil() {
+			return true
+		}
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		return err == nil && v == nil
+	}
+	return false
+}
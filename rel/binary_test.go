@@ -0,0 +1,52 @@
+package rel
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparisons(t *testing.T) {
+	f := Field{"age"}
+	tests := []struct {
+		expr  Expr
+		query string
+	}{
+		{Equality{f, BindParam{1}}, `"age" = ?`},
+		{Equality{f, BindParam{sql.NullInt64{}}}, `"age" IS NULL`},
+		{NotEqual{f, BindParam{1}}, `"age" <> ?`},
+		{NotEqual{f, nil}, `"age" IS NOT NULL`},
+		{NotEqual{f, BindParam{(*int)(nil)}}, `"age" IS NOT NULL`},
+		{Equality{f, nil}, `"age" IS NULL`},
+		{IsNotNull{f}, `"age" IS NOT NULL`},
+		{LessThan{f, BindParam{1}}, `"age" < ?`},
+		{LessThanOrEqual{f, BindParam{1}}, `"age" <= ?`},
+		{GreaterThan{f, BindParam{1}}, `"age" > ?`},
+		{GreaterThanOrEqual{f, BindParam{1}}, `"age" >= ?`},
+		{Between{f, BindParam{1}, BindParam{2}}, `"age" BETWEEN ? AND ?`},
+		{In{f, nil}, `1=0`},
+		{In{f, ExprList{BindParam{1}, BindParam{2}}}, `"age" IN (?, ?)`},
+		{NotIn{f, nil}, `1=1`},
+		{NotIn{f, ExprList{BindParam{1}, BindParam{2}}}, `"age" NOT IN (?, ?)`},
+		{Like{Field: f, Pattern: BindParam{"1%"}}, `"age" LIKE ? ESCAPE '!'`},
+		{Like{Field: f, Pattern: BindParam{"1%"}, CaseInsensitive: true}, `LOWER("age") LIKE LOWER(?) ESCAPE '!'`},
+	}
+	for _, test := range tests {
+		stmt := DeleteStatement{Table: "t", Wheres: []Expr{test.expr}}
+		query, _ := stmt.Build()
+		require.Equal(t, `DELETE FROM "t" WHERE `+test.query, query)
+	}
+
+	stmt := DeleteStatement{
+		Dialect: Postgres,
+		Table:   "t",
+		Wheres:  []Expr{Like{Field: f, Pattern: BindParam{"1%"}, CaseInsensitive: true}},
+	}
+	query, _ := stmt.Build()
+	require.Equal(t, `DELETE FROM "t" WHERE "age" ILIKE $1 ESCAPE '!'`, query)
+}
+
+func TestEscapeLike(t *testing.T) {
+	require.Equal(t, "100!% !_!!", EscapeLike("100% _!"))
+}
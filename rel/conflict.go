@@ -0,0 +1,62 @@
+package rel
+
+import "strings"
+
+// OnConflict makes an insert skip or update the rows that conflict with
+// existing ones on a unique index
+type OnConflict struct {
+	// Columns are the columns of the unique index. MySQL doesn't take them,
+	// it considers every unique index.
+	Columns []string
+
+	// Update has the assignments for the existing rows. Without any, the
+	// conflicting rows are skipped. Excluded refers to the values the rows
+	// would have been inserted with.
+	Update []Assignment
+}
+
+// clause is the dialect's clause for the conflicts, or "" for INSERT IGNORE
+func (o *OnConflict) clause(c *collector, columns []string) string {
+	return c.dialect.OnConflict(o.Columns, columns, len(o.Update) > 0)
+}
+
+func (o *OnConflict) writeTo(c *collector, columns []string) {
+	clause := o.clause(c, columns)
+	if clause == "" {
+		return
+	}
+	c.WriteString(" ")
+	c.WriteString(clause)
+	for i, assignment := range o.Update {
+		if i > 0 {
+			c.WriteString(",")
+		}
+		c.WriteString(" ")
+		assignment.writeTo(c)
+	}
+}
+
+// Excluded is the value an insert had for the column in a row that conflicted
+type Excluded struct {
+	Column string
+}
+
+func (e Excluded) writeTo(c *collector) {
+	c.WriteString(c.dialect.Excluded(e.Column))
+}
+
+// onConflict is the ON CONFLICT clause of SQLite and PostgreSQL
+func onConflict(d Dialect, target []string, update bool) string {
+	clause := "ON CONFLICT"
+	if len(target) > 0 {
+		quoted := make([]string, len(target))
+		for i, column := range target {
+			quoted[i] = d.QuoteIdentifier(column)
+		}
+		clause += " (" + strings.Join(quoted, ", ") + ")"
+	}
+	if update {
+		return clause + " DO UPDATE SET"
+	}
+	return clause + " DO NOTHING"
+}
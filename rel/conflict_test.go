@@ -0,0 +1,54 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnConflict(t *testing.T) {
+	update := &OnConflict{
+		Columns: []string{"email"},
+		Update: []Assignment{
+			{Field: Field{"name"}, Value: Excluded{"name"}},
+			{Field: Field{"visits"}, Value: Literal{Text: "visits + 1"}},
+		},
+	}
+	skip := &OnConflict{Columns: []string{"email"}}
+
+	tests := []struct {
+		dialect    Dialect
+		onConflict *OnConflict
+		query      string
+	}{
+		{SQLite, update, `INSERT INTO "users" ("email", "name") VALUES (?, ?) ON CONFLICT ("email") DO UPDATE SET "name" = excluded."name", "visits" = visits + 1`},
+		{Postgres, update, `INSERT INTO "users" ("email", "name") VALUES ($1, $2) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "visits" = visits + 1`},
+		{MySQL, update, "INSERT INTO `users` (`email`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `visits` = visits + 1"},
+		{SQLite, skip, `INSERT INTO "users" ("email", "name") VALUES (?, ?) ON CONFLICT ("email") DO NOTHING`},
+		{Postgres, &OnConflict{}, `INSERT INTO "users" ("email", "name") VALUES ($1, $2) ON CONFLICT DO NOTHING`},
+		{MySQL, skip, "INSERT INTO `users` (`email`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `email` = `email`"},
+	}
+	for _, test := range tests {
+		stmt := InsertStatement{
+			Dialect:    test.dialect,
+			Table:      "users",
+			Columns:    []string{"email", "name"},
+			Values:     []Expr{BindParam{"a@example.com"}, BindParam{"A"}},
+			OnConflict: test.onConflict,
+		}
+		query, values := stmt.Build()
+		require.Equal(t, test.query, query)
+		require.Equal(t, []interface{}{"a@example.com", "A"}, values)
+	}
+}
+
+func TestOnConflictWithoutColumns(t *testing.T) {
+	stmt := InsertStatement{
+		Dialect:    MySQL,
+		Table:      "tags",
+		Values:     []Expr{BindParam{"go"}},
+		OnConflict: &OnConflict{},
+	}
+	query, _ := stmt.Build()
+	require.Equal(t, "INSERT IGNORE INTO `tags` VALUES (?)", query)
+}
@@ -1,24 +1,17 @@
 package rel
 
 type DeleteStatement struct {
-	Table  string
-	Wheres []Expr
+	Dialect Dialect
+	Table   string
+	Wheres  []Expr
 }
 
 func (s *DeleteStatement) Build() (string, []interface{}) {
-	var c collector
+	c := newCollector(s.Dialect)
 	c.WriteString("DELETE FROM ")
-	c.WriteString(s.Table)
+	c.writeIdentifier(s.Table)
 
-	if len(s.Wheres) > 0 {
-		c.WriteString(" WHERE ")
-		for i, where := range s.Wheres {
-			if i > 0 {
-				c.WriteString(" AND ")
-			}
-			where.writeTo(&c)
-		}
-	}
+	writeWheres(c, s.Wheres)
 
 	return c.String(), c.values
 }
@@ -0,0 +1,244 @@
+package rel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect holds the parts of the SQL syntax that differ between databases
+type Dialect interface {
+	// Placeholder returns the placeholder for the n-th bind parameter, counting from 1
+	Placeholder(n int) string
+
+	// QuoteIdentifier quotes a table or column name
+	QuoteIdentifier(name string) string
+
+	// LimitOffset returns the clause limiting the selected rows, or "" if
+	// both are zero. A zero limit means no limit.
+	LimitOffset(limit, offset int64) string
+
+	// Bool returns the literal for a boolean value
+	Bool(b bool) string
+
+	// CaseInsensitiveLike returns the operator for case-insensitive LIKE
+	// matches, or "" if there is none
+	CaseInsensitiveLike() string
+
+	// MaxParams is the largest number of bind parameters a statement can have
+	MaxParams() int
+
+	// FirstInsertID returns the ID assigned to the first of the n rows
+	// inserted by a statement, given the result's LastInsertId. The IDs of
+	// the rows follow it. ok is false if the database doesn't report it.
+	FirstInsertID(lastInsertID int64, n int) (id int64, ok bool)
+
+	// OnConflict returns the clause of an insert for rows that conflict with
+	// existing ones on the target columns. With update, it's followed by
+	// assignments, otherwise the rows are skipped. The columns are the
+	// inserted ones. It's empty if the rows are skipped with INSERT IGNORE
+	// instead.
+	OnConflict(target, columns []string, update bool) string
+
+	// Excluded returns the value an insert had for the column in a row that
+	// conflicted
+	Excluded(column string) string
+
+	// Returning reports whether inserts and updates can have a RETURNING
+	// clause
+	Returning() bool
+}
+
+var (
+	SQLite   Dialect = sqliteDialect{}
+	Postgres Dialect = postgresDialect{}
+	MySQL    Dialect = mysqlDialect{}
+
+	// SQLiteReturning is SQLite 3.35.0 or later, which has RETURNING
+	SQLiteReturning Dialect = sqliteDialect{returning: true}
+)
+
+type sqliteDialect struct {
+	returning bool
+}
+
+func (sqliteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (sqliteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, '"')
+}
+
+func (sqliteDialect) LimitOffset(limit, offset int64) string {
+	switch {
+	case limit == 0 && offset == 0:
+		return ""
+	case offset == 0:
+		return fmt.Sprintf("LIMIT %d", limit)
+	case limit == 0:
+		// OFFSET needs a LIMIT, and a negative one means there is none
+		return fmt.Sprintf("LIMIT -1 OFFSET %d", offset)
+	default:
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	}
+}
+
+func (sqliteDialect) Bool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+func (sqliteDialect) CaseInsensitiveLike() string {
+	return ""
+}
+
+// MaxParams is SQLite's default limit before version 3.32.0
+func (sqliteDialect) MaxParams() int {
+	return 999
+}
+
+// FirstInsertID counts back from the ID of the last row, which is what SQLite reports
+func (sqliteDialect) FirstInsertID(lastInsertID int64, n int) (int64, bool) {
+	return lastInsertID - int64(n) + 1, true
+}
+
+func (d sqliteDialect) OnConflict(target, columns []string, update bool) string {
+	return onConflict(d, target, update)
+}
+
+func (d sqliteDialect) Excluded(column string) string {
+	return "excluded." + d.QuoteIdentifier(column)
+}
+
+// Returning is supported since SQLite 3.35.0, which not every build has, so
+// it's only used with SQLiteReturning
+func (d sqliteDialect) Returning() bool {
+	return d.returning
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (postgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, '"')
+}
+
+func (postgresDialect) LimitOffset(limit, offset int64) string {
+	var clauses []string
+	if limit != 0 {
+		clauses = append(clauses, fmt.Sprintf("LIMIT %d", limit))
+	}
+	if offset != 0 {
+		clauses = append(clauses, fmt.Sprintf("OFFSET %d", offset))
+	}
+	return strings.Join(clauses, " ")
+}
+
+func (postgresDialect) Bool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (postgresDialect) CaseInsensitiveLike() string {
+	return "ILIKE"
+}
+
+func (postgresDialect) MaxParams() int {
+	return 65535
+}
+
+// FirstInsertID isn't known, since PostgreSQL has no LastInsertId
+func (postgresDialect) FirstInsertID(lastInsertID int64, n int) (int64, bool) {
+	return 0, false
+}
+
+func (d postgresDialect) OnConflict(target, columns []string, update bool) string {
+	return onConflict(d, target, update)
+}
+
+func (d postgresDialect) Excluded(column string) string {
+	return "EXCLUDED." + d.QuoteIdentifier(column)
+}
+
+func (postgresDialect) Returning() bool {
+	return true
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (mysqlDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, '`')
+}
+
+func (mysqlDialect) LimitOffset(limit, offset int64) string {
+	switch {
+	case limit == 0 && offset == 0:
+		return ""
+	case offset == 0:
+		return fmt.Sprintf("LIMIT %d", limit)
+	case limit == 0:
+		// OFFSET needs a LIMIT, so use the largest one there is
+		return fmt.Sprintf("LIMIT 18446744073709551615 OFFSET %d", offset)
+	default:
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	}
+}
+
+func (mysqlDialect) Bool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+func (mysqlDialect) CaseInsensitiveLike() string {
+	return ""
+}
+
+func (mysqlDialect) MaxParams() int {
+	return 65535
+}
+
+// FirstInsertID is the ID MySQL reports, which is the first row's
+func (mysqlDialect) FirstInsertID(lastInsertID int64, n int) (int64, bool) {
+	return lastInsertID, true
+}
+
+// OnConflict skips rows by assigning a column to itself, since INSERT IGNORE
+// would ignore other errors as well. Without any columns to assign, the
+// insert falls back to INSERT IGNORE.
+func (d mysqlDialect) OnConflict(target, columns []string, update bool) string {
+	if update {
+		return "ON DUPLICATE KEY UPDATE"
+	}
+	if len(columns) == 0 {
+		return ""
+	}
+	column := d.QuoteIdentifier(columns[0])
+	return "ON DUPLICATE KEY UPDATE " + column + " = " + column
+}
+
+func (d mysqlDialect) Excluded(column string) string {
+	return "VALUES(" + d.QuoteIdentifier(column) + ")"
+}
+
+// Returning isn't supported, only MariaDB has it
+func (mysqlDialect) Returning() bool {
+	return false
+}
+
+func quoteIdentifier(name string, quote byte) string {
+	q := string(quote)
+	return q + strings.Replace(name, q, q+q, -1) + q
+}
@@ -0,0 +1,75 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectDialects(t *testing.T) {
+	stmt := SelectStatement{
+		Table:   "users",
+		Columns: []Expr{Literal{Text: "id"}},
+		Wheres: []Expr{
+			Equality{Field: Field{"first_name"}, Value: BindParam{"Bouke"}},
+			Literal{Text: "age > ? AND note <> '?' AND age < ?", Params: []interface{}{18, 65}},
+			Literal{Text: "active = ?", Params: []interface{}{true}},
+		},
+		Offset: 10,
+	}
+
+	tests := []struct {
+		dialect Dialect
+		query   string
+	}{
+		{nil, `SELECT id FROM "users" WHERE "first_name" = ? AND (age > ? AND note <> '?' AND age < ?) AND (active = ?) LIMIT -1 OFFSET 10`},
+		{SQLite, `SELECT id FROM "users" WHERE "first_name" = ? AND (age > ? AND note <> '?' AND age < ?) AND (active = ?) LIMIT -1 OFFSET 10`},
+		{Postgres, `SELECT id FROM "users" WHERE "first_name" = $1 AND (age > $2 AND note <> '?' AND age < $3) AND (active = $4) OFFSET 10`},
+		{MySQL, "SELECT id FROM `users` WHERE `first_name` = ? AND (age > ? AND note <> '?' AND age < ?) AND (active = ?) LIMIT 18446744073709551615 OFFSET 10"},
+	}
+	for _, test := range tests {
+		stmt.Dialect = test.dialect
+		query, values := stmt.Build()
+		require.Equal(t, test.query, query)
+		require.Equal(t, []interface{}{"Bouke", 18, 65, true}, values)
+	}
+}
+
+func TestLimitOffset(t *testing.T) {
+	for _, d := range []Dialect{SQLite, Postgres, MySQL} {
+		require.Equal(t, "", d.LimitOffset(0, 0))
+		require.Equal(t, "LIMIT 5", d.LimitOffset(5, 0))
+		require.Equal(t, "LIMIT 5 OFFSET 10", d.LimitOffset(5, 10))
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	require.Equal(t, `"order"`, SQLite.QuoteIdentifier("order"))
+	require.Equal(t, `"a""b"`, Postgres.QuoteIdentifier(`a"b`))
+	require.Equal(t, "`a``b`", MySQL.QuoteIdentifier("a`b"))
+}
+
+func TestBool(t *testing.T) {
+	stmt := UpdateStatement{
+		Table:  "users",
+		Values: []Expr{Assignment{Field: Field{"admin"}, Value: Bool(true)}},
+		Wheres: []Expr{Equality{Field: Field{"id"}, Value: BindParam{1}}},
+	}
+	query, _ := stmt.Build()
+	require.Equal(t, `UPDATE "users" SET "admin" = 1 WHERE "id" = ?`, query)
+
+	stmt.Dialect = Postgres
+	query, _ = stmt.Build()
+	require.Equal(t, `UPDATE "users" SET "admin" = TRUE WHERE "id" = $1`, query)
+}
+
+func TestFirstInsertID(t *testing.T) {
+	id, ok := SQLite.FirstInsertID(10, 3)
+	require.True(t, ok)
+	require.EqualValues(t, 8, id)
+	id, ok = MySQL.FirstInsertID(10, 3)
+	require.True(t, ok)
+	require.EqualValues(t, 10, id)
+	_, ok = Postgres.FirstInsertID(10, 3)
+	require.False(t, ok)
+}
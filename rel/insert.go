@@ -1,34 +1,73 @@
 package rel
 
 type InsertStatement struct {
+	Dialect Dialect
 	Table   string
 	Columns []string
-	Values  []Expr
+
+	// Values is a row to insert, before the Rows
+	Values []Expr
+	Rows   [][]Expr
+
+	OnConflict *OnConflict
+
+	// Returning are the columns of the inserted rows to return, for dialects
+	// that support it
+	Returning []string
 }
 
 func (s *InsertStatement) Build() (string, []interface{}) {
-	var c collector
-	c.WriteString("INSERT INTO ")
-	c.WriteString(s.Table)
-	c.WriteString(" ")
+	c := newCollector(s.Dialect)
+	if s.OnConflict != nil && s.OnConflict.clause(c, s.Columns) == "" {
+		c.WriteString("INSERT IGNORE INTO ")
+	} else {
+		c.WriteString("INSERT INTO ")
+	}
+	c.writeIdentifier(s.Table)
 	if len(s.Columns) > 0 {
-		c.WriteString("(")
+		c.WriteString(" (")
 		for i, value := range s.Columns {
 			if i > 0 {
 				c.WriteString(", ")
 			}
-			c.WriteString(value)
+			c.writeIdentifier(value)
 		}
 		c.WriteString(")")
 	}
-	c.WriteString(" VALUES (")
-	for i, value := range s.Values {
+	c.WriteString(" VALUES ")
+	rows := s.Rows
+	if len(s.Values) > 0 || len(rows) == 0 {
+		rows = append([][]Expr{s.Values}, rows...)
+	}
+	for i, row := range rows {
 		if i > 0 {
 			c.WriteString(", ")
 		}
-		value.writeTo(&c)
+		c.WriteString("(")
+		for j, value := range row {
+			if j > 0 {
+				c.WriteString(", ")
+			}
+			value.writeTo(c)
+		}
+		c.WriteString(")")
+	}
+	if s.OnConflict != nil {
+		s.OnConflict.writeTo(c, s.Columns)
 	}
-	c.WriteString(")")
+	writeReturning(c, s.Returning)
 
 	return c.String(), c.values
 }
+
+func writeReturning(c *collector, columns []string) {
+	if len(columns) > 0 {
+		c.WriteString(" RETURNING ")
+		for i, column := range columns {
+			if i > 0 {
+				c.WriteString(", ")
+			}
+			c.writeIdentifier(column)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertRows(t *testing.T) {
+	stmt := InsertStatement{
+		Dialect: Postgres,
+		Table:   "users",
+		Columns: []string{"first_name", "last_name"},
+		Rows: [][]Expr{
+			{BindParam{"Alice"}, BindParam{"Smith"}},
+			{BindParam{"Bob"}, BindParam{"Jones"}},
+		},
+	}
+	query, values := stmt.Build()
+	require.Equal(t, `INSERT INTO "users" ("first_name", "last_name") VALUES ($1, $2), ($3, $4)`, query)
+	require.Equal(t, []interface{}{"Alice", "Smith", "Bob", "Jones"}, values)
+
+	stmt.Values = []Expr{BindParam{"Carol"}, BindParam{"White"}}
+	query, _ = stmt.Build()
+	require.Equal(t, `INSERT INTO "users" ("first_name", "last_name") VALUES ($1, $2), ($3, $4), ($5, $6)`, query)
+}
+
+func TestReturning(t *testing.T) {
+	insert := InsertStatement{
+		Dialect:    Postgres,
+		Table:      "users",
+		Columns:    []string{"email"},
+		Values:     []Expr{BindParam{"a@example.com"}},
+		OnConflict: &OnConflict{Columns: []string{"email"}},
+		Returning:  []string{"id", "created_at"},
+	}
+	query, _ := insert.Build()
+	require.Equal(t, `INSERT INTO "users" ("email") VALUES ($1) ON CONFLICT ("email") DO NOTHING RETURNING "id", "created_at"`, query)
+
+	update := UpdateStatement{
+		Table:     "users",
+		Values:    []Expr{Assignment{Field: Field{"email"}, Value: BindParam{"b@example.com"}}},
+		Wheres:    []Expr{Equality{Field: Field{"id"}, Value: BindParam{1}}},
+		Returning: []string{"updated_at"},
+	}
+	query, values := update.Build()
+	require.Equal(t, `UPDATE "users" SET "email" = ? WHERE "id" = ? RETURNING "updated_at"`, query)
+	require.Equal(t, []interface{}{"b@example.com", 1}, values)
+
+	require.False(t, SQLite.Returning())
+	require.True(t, SQLiteReturning.Returning())
+	require.True(t, Postgres.Returning())
+	require.False(t, MySQL.Returning())
+}
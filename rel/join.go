@@ -0,0 +1,29 @@
+package rel
+
+// InnerJoin selects the rows of both tables matching the On condition
+type InnerJoin struct {
+	Table string
+	On    Expr
+}
+
+func (j InnerJoin) writeTo(c *collector) {
+	writeJoin(c, "INNER JOIN ", j.Table, j.On)
+}
+
+// LeftOuterJoin is like an InnerJoin, but also selects the rows of the left
+// table that have no match, with NULL for the joined table's columns
+type LeftOuterJoin struct {
+	Table string
+	On    Expr
+}
+
+func (j LeftOuterJoin) writeTo(c *collector) {
+	writeJoin(c, "LEFT OUTER JOIN ", j.Table, j.On)
+}
+
+func writeJoin(c *collector, join, table string, on Expr) {
+	c.WriteString(join)
+	c.writeIdentifier(table)
+	c.WriteString(" ON ")
+	on.writeTo(c)
+}
@@ -0,0 +1,69 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoins(t *testing.T) {
+	stmt := SelectStatement{
+		Table:   "users",
+		Columns: []Expr{Field{"users.*"}},
+		Joins: []Expr{
+			InnerJoin{Table: "posts", On: Equality{Field{"posts.user_id"}, Field{"users.id"}}},
+			LeftOuterJoin{Table: "categories", On: Equality{Field{"categories.slug"}, Field{"posts.category_id"}}},
+		},
+		Wheres: []Expr{Equality{Field{"posts.body"}, BindParam{"Hello"}}},
+	}
+	query, values := stmt.Build()
+	require.Equal(t, `SELECT "users".* FROM "users" INNER JOIN "posts" ON "posts"."user_id" = "users"."id" LEFT OUTER JOIN "categories" ON "categories"."slug" = "posts"."category_id" WHERE "posts"."body" = ?`, query)
+	require.Equal(t, []interface{}{"Hello"}, values)
+}
+
+func TestSubquery(t *testing.T) {
+	stmt := DeleteStatement{
+		Dialect: Postgres,
+		Table:   "users",
+		Wheres: []Expr{
+			Equality{Field{"users.first_name"}, BindParam{"Bouke"}},
+			In{
+				Left: Grouping{Field{"users.id"}},
+				Right: ExprList{&SelectStatement{
+					Table:   "users",
+					Columns: []Expr{Field{"users.id"}},
+					Joins:   []Expr{InnerJoin{Table: "posts", On: Equality{Field{"posts.user_id"}, Field{"users.id"}}}},
+					Wheres:  []Expr{Equality{Field{"posts.body"}, BindParam{"Hello"}}},
+				}},
+			},
+		},
+	}
+	query, values := stmt.Build()
+	require.Equal(t, `DELETE FROM "users" WHERE "users"."first_name" = $1 AND ("users"."id") IN (SELECT "users"."id" FROM "users" INNER JOIN "posts" ON "posts"."user_id" = "users"."id" WHERE "posts"."body" = $2)`, query)
+	require.Equal(t, []interface{}{"Bouke", "Hello"}, values)
+}
+
+func TestDerivedTable(t *testing.T) {
+	stmt := DeleteStatement{
+		Dialect: MySQL,
+		Table:   "users",
+		Wheres: []Expr{
+			In{
+				Left: Grouping{Field{"users.id"}},
+				Right: ExprList{&SelectStatement{
+					Table:   "keys",
+					Columns: []Expr{Field{"keys.id"}},
+					From: &SelectStatement{
+						Table:   "users",
+						Columns: []Expr{Field{"users.id"}},
+						Joins:   []Expr{InnerJoin{Table: "posts", On: Equality{Field{"posts.user_id"}, Field{"users.id"}}}},
+						Wheres:  []Expr{Equality{Field{"posts.body"}, BindParam{"Hello"}}},
+					},
+				}},
+			},
+		},
+	}
+	query, values := stmt.Build()
+	require.Equal(t, "DELETE FROM `users` WHERE (`users`.`id`) IN (SELECT `keys`.`id` FROM (SELECT `users`.`id` FROM `users` INNER JOIN `posts` ON `posts`.`user_id` = `users`.`id` WHERE `posts`.`body` = ?) AS `keys`)", query)
+	require.Equal(t, []interface{}{"Hello"}, values)
+}
@@ -0,0 +1,78 @@
+package rel
+
+// And is true when all of its expressions are. An empty And is always true.
+type And []Expr
+
+func (a And) writeTo(c *collector) {
+	writeJoined(c, a, " AND ", "1=1")
+}
+
+// Or is true when any of its expressions is. An empty Or is always false.
+type Or []Expr
+
+func (o Or) writeTo(c *collector) {
+	writeJoined(c, o, " OR ", "1=0")
+}
+
+// Not negates an expression
+type Not struct {
+	Expr Expr
+}
+
+func (n Not) writeTo(c *collector) {
+	c.WriteString("NOT ")
+	Grouping{n.Expr}.writeTo(c)
+}
+
+// Grouping puts an expression in parentheses
+type Grouping struct {
+	Expr Expr
+}
+
+func (g Grouping) writeTo(c *collector) {
+	c.WriteString("(")
+	g.Expr.writeTo(c)
+	c.WriteString(")")
+}
+
+func writeJoined(c *collector, exprs []Expr, op, empty string) {
+	switch len(exprs) {
+	case 0:
+		c.WriteString(empty)
+	case 1:
+		exprs[0].writeTo(c)
+	default:
+		for i, expr := range exprs {
+			if i > 0 {
+				c.WriteString(op)
+			}
+			if needsGrouping(expr) {
+				expr = Grouping{expr}
+			}
+			expr.writeTo(c)
+		}
+	}
+}
+
+// needsGrouping reports whether the expression could bind differently when
+// combined with AND or OR. Literals can hold any SQL, so they always do.
+func needsGrouping(expr Expr) bool {
+	switch e := expr.(type) {
+	case And:
+		return len(e) > 1 || len(e) == 1 && needsGrouping(e[0])
+	case Or:
+		return len(e) > 1 || len(e) == 1 && needsGrouping(e[0])
+	case Literal, *Literal:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeWheres writes the WHERE clause of a statement
+func writeWheres(c *collector, wheres []Expr) {
+	if len(wheres) > 0 {
+		c.WriteString(" WHERE ")
+		And(wheres).writeTo(c)
+	}
+}
@@ -0,0 +1,35 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogical(t *testing.T) {
+	a := Equality{Field: Field{"a"}, Value: BindParam{1}}
+	b := Equality{Field: Field{"b"}, Value: BindParam{2}}
+	lit := Literal{Text: "c = ? OR d = ?", Params: []interface{}{3, 4}}
+
+	tests := []struct {
+		wheres []Expr
+		query  string
+	}{
+		{[]Expr{lit}, `DELETE FROM "t" WHERE c = ? OR d = ?`},
+		{[]Expr{a, lit}, `DELETE FROM "t" WHERE "a" = ? AND (c = ? OR d = ?)`},
+		{[]Expr{Or{a, b}}, `DELETE FROM "t" WHERE "a" = ? OR "b" = ?`},
+		{[]Expr{Or{a, b}, lit}, `DELETE FROM "t" WHERE ("a" = ? OR "b" = ?) AND (c = ? OR d = ?)`},
+		{[]Expr{Or{And{a, b}, lit}}, `DELETE FROM "t" WHERE ("a" = ? AND "b" = ?) OR (c = ? OR d = ?)`},
+		{[]Expr{a, Or{And{lit}}}, `DELETE FROM "t" WHERE "a" = ? AND (c = ? OR d = ?)`},
+		{[]Expr{Not{a}}, `DELETE FROM "t" WHERE NOT ("a" = ?)`},
+		{[]Expr{Not{And{a, b}}, b}, `DELETE FROM "t" WHERE NOT ("a" = ? AND "b" = ?) AND "b" = ?`},
+		{[]Expr{And{}}, `DELETE FROM "t" WHERE 1=1`},
+		{[]Expr{Or{}}, `DELETE FROM "t" WHERE 1=0`},
+		{[]Expr{Or{And{}, a}}, `DELETE FROM "t" WHERE 1=1 OR "a" = ?`},
+	}
+	for _, test := range tests {
+		stmt := DeleteStatement{Table: "t", Wheres: test.wheres}
+		query, _ := stmt.Build()
+		require.Equal(t, test.query, query)
+	}
+}
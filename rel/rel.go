@@ -2,11 +2,41 @@ package rel
 
 import (
 	"bytes"
+	"strings"
 )
 
 type collector struct {
 	bytes.Buffer
-	values []interface{}
+	dialect Dialect
+	values  []interface{}
+}
+
+// newCollector starts building a statement, for SQLite if the dialect is nil
+func newCollector(d Dialect) *collector {
+	if d == nil {
+		d = SQLite
+	}
+	return &collector{dialect: d}
+}
+
+// writeIdentifier writes a quoted name, which may be qualified like table.column
+func (c *collector) writeIdentifier(name string) {
+	for i, part := range strings.Split(name, ".") {
+		if i > 0 {
+			c.WriteString(".")
+		}
+		if part == "*" {
+			c.WriteString(part)
+		} else {
+			c.WriteString(c.dialect.QuoteIdentifier(part))
+		}
+	}
+}
+
+// bind adds a parameter and writes its placeholder
+func (c *collector) bind(value interface{}) {
+	c.values = append(c.values, value)
+	c.WriteString(c.dialect.Placeholder(len(c.values)))
 }
 
 type Expr interface {
@@ -18,8 +48,7 @@ type BindParam struct {
 }
 
 func (b BindParam) writeTo(c *collector) {
-	c.WriteString("?")
-	c.values = append(c.values, b.Value)
+	c.bind(b.Value)
 }
 
 type ExprList []Expr
@@ -33,14 +62,46 @@ func (e ExprList) writeTo(c *collector) {
 	}
 }
 
+// Literal is a piece of SQL with ? placeholders for its Params, which are
+// rewritten to the dialect's placeholders. Question marks in quoted strings
+// and identifiers are left alone, as is the text of a Literal without Params.
 type Literal struct {
 	Text   string
 	Params []interface{}
 }
 
 func (l Literal) writeTo(c *collector) {
-	c.WriteString(l.Text)
-	c.values = append(c.values, l.Params...)
+	if len(l.Params) == 0 {
+		c.WriteString(l.Text)
+		return
+	}
+
+	params := l.Params
+	var quote rune
+	for _, r := range l.Text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'', r == '"', r == '`':
+			quote = r
+		case r == '?' && len(params) > 0:
+			c.bind(params[0])
+			params = params[1:]
+			continue
+		}
+		c.WriteRune(r)
+	}
+	// Extra params are passed on, so the database reports the mismatch
+	c.values = append(c.values, params...)
+}
+
+// Bool is a boolean literal
+type Bool bool
+
+func (b Bool) writeTo(c *collector) {
+	c.WriteString(c.dialect.Bool(bool(b)))
 }
 
 type Ascending struct {
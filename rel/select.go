@@ -1,40 +1,64 @@
 package rel
 
-import (
-	"fmt"
-)
-
 type SelectStatement struct {
+	Dialect Dialect
 	Table   string
+
+	// From is a subquery to select from instead of the table, which is then
+	// the name it gets
+	From *SelectStatement
+
 	Columns []Expr
+	Joins   []Expr
 	Wheres  []Expr
+	Groups  []Expr
+	Havings []Expr
 	Orders  []Expr
 	Limit   int64
 	Offset  int64
 }
 
 func (s *SelectStatement) Build() (string, []interface{}) {
-	var c collector
+	c := newCollector(s.Dialect)
+	s.writeTo(c)
+
+	return c.String(), c.values
+}
+
+// writeTo makes a SelectStatement usable as a subquery, like in an In expression
+func (s *SelectStatement) writeTo(c *collector) {
 	c.WriteString("SELECT ")
 
 	for i, col := range s.Columns {
 		if i > 0 {
 			c.WriteString(", ")
 		}
-		col.writeTo(&c)
+		col.writeTo(c)
 	}
 
 	c.WriteString(" FROM ")
-	c.WriteString(s.Table)
+	if s.From != nil {
+		c.WriteString("(")
+		s.From.writeTo(c)
+		c.WriteString(") AS ")
+	}
+	c.writeIdentifier(s.Table)
 
-	if len(s.Wheres) > 0 {
-		c.WriteString(" WHERE ")
-		for i, where := range s.Wheres {
-			if i > 0 {
-				c.WriteString(" AND ")
-			}
-			where.writeTo(&c)
-		}
+	for _, join := range s.Joins {
+		c.WriteString(" ")
+		join.writeTo(c)
+	}
+
+	writeWheres(c, s.Wheres)
+
+	if len(s.Groups) > 0 {
+		c.WriteString(" GROUP BY ")
+		ExprList(s.Groups).writeTo(c)
+	}
+
+	if len(s.Havings) > 0 {
+		c.WriteString(" HAVING ")
+		And(s.Havings).writeTo(c)
 	}
 
 	if len(s.Orders) > 0 {
@@ -43,16 +67,12 @@ func (s *SelectStatement) Build() (string, []interface{}) {
 			if i > 0 {
 				c.WriteString(", ")
 			}
-			order.writeTo(&c)
+			order.writeTo(c)
 		}
 	}
 
-	if s.Limit != 0 {
-		fmt.Fprintf(&c, " LIMIT %d", s.Limit)
-	}
-	if s.Offset != 0 {
-		fmt.Fprintf(&c, " OFFSET %d", s.Offset)
+	if clause := c.dialect.LimitOffset(s.Limit, s.Offset); clause != "" {
+		c.WriteString(" ")
+		c.WriteString(clause)
 	}
-
-	return c.String(), c.values
 }
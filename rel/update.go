@@ -1,32 +1,30 @@
 package rel
 
 type UpdateStatement struct {
-	Table  string
-	Values []Expr
-	Wheres []Expr
+	Dialect Dialect
+	Table   string
+	Values  []Expr
+	Wheres  []Expr
+
+	// Returning are the columns of the updated rows to return, for dialects
+	// that support it
+	Returning []string
 }
 
 func (s *UpdateStatement) Build() (string, []interface{}) {
-	var c collector
+	c := newCollector(s.Dialect)
 	c.WriteString("UPDATE ")
-	c.WriteString(s.Table)
+	c.writeIdentifier(s.Table)
 	c.WriteString(" SET ")
 	for i, value := range s.Values {
 		if i > 0 {
 			c.WriteString(", ")
 		}
-		value.writeTo(&c)
+		value.writeTo(c)
 	}
 
-	if len(s.Wheres) > 0 {
-		c.WriteString(" WHERE ")
-		for i, where := range s.Wheres {
-			if i > 0 {
-				c.WriteString(" AND ")
-			}
-			where.writeTo(&c)
-		}
-	}
+	writeWheres(c, s.Wheres)
+	writeReturning(c, s.Returning)
 
 	return c.String(), c.values
 }
@@ -3,6 +3,7 @@ package rel
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // UnpackWhere creates a list of expression from a Where call
@@ -32,8 +33,12 @@ func UnpackWhere(value interface{}, args ...interface{}) ([]Expr, error) {
 
 		exprs := make([]Expr, 0, v.Len())
 		for i := v.MapRange(); i.Next(); {
+			name := i.Key().String()
+			if !validFieldName(name) {
+				return nil, fmt.Errorf("invalid field name %q", name)
+			}
 			exprs = append(exprs, Equality{
-				Field: Field{i.Key().String()},
+				Field: Field{name},
 				Value: BindParam{i.Value().Interface()},
 			})
 		}
@@ -41,3 +46,15 @@ func UnpackWhere(value interface{}, args ...interface{}) ([]Expr, error) {
 		return exprs, nil
 	}
 }
+
+// validFieldName checks a column name coming from a map key, which may be
+// qualified with a table name. Names are quoted when written, this only
+// rejects the ones that can't be columns.
+func validFieldName(name string) bool {
+	for _, part := range strings.Split(name, ".") {
+		if part == "" || part == "*" || strings.ContainsRune(part, 0) {
+			return false
+		}
+	}
+	return strings.Count(name, ".") <= 1
+}
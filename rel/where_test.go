@@ -0,0 +1,44 @@
+package rel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackWhereMap(t *testing.T) {
+	exprs, err := UnpackWhere(map[string]interface{}{"order": 1})
+	require.NoError(t, err)
+
+	stmt := SelectStatement{
+		Table:   "group",
+		Columns: []Expr{Field{"group.*"}},
+		Wheres:  exprs,
+	}
+	query, values := stmt.Build()
+	require.Equal(t, `SELECT "group".* FROM "group" WHERE "order" = ?`, query)
+	require.Equal(t, []interface{}{1}, values)
+
+	exprs, err = UnpackWhere(map[string]interface{}{`name" = '' OR "1`: 1})
+	require.NoError(t, err)
+	stmt.Wheres = exprs
+	query, _ = stmt.Build()
+	require.Equal(t, `SELECT "group".* FROM "group" WHERE "name"" = '' OR ""1" = ?`, query)
+
+	for _, name := range []string{"", "users.", "*", "a.b.c", "users.*"} {
+		_, err := UnpackWhere(map[string]interface{}{name: 1})
+		require.EqualError(t, err, fmt.Sprintf("invalid field name %q", name))
+	}
+}
+
+func TestInsertQuoting(t *testing.T) {
+	stmt := InsertStatement{
+		Dialect: MySQL,
+		Table:   "user",
+		Columns: []string{"key", "order"},
+		Values:  []Expr{BindParam{1}, BindParam{2}},
+	}
+	query, _ := stmt.Build()
+	require.Equal(t, "INSERT INTO `user` (`key`, `order`) VALUES (?, ?)", query)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// LoadInput reads the schema document at path. Files ending in .yaml or .yml
+// are parsed as YAML, everything else as JSON. The input isn't validated, so
+// callers can fill in missing settings first.
+func LoadInput(path string) (*Input, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var input *Input
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		input, err = ParseYAMLInput(data)
+	default:
+		input, err = ParseJSONInput(data)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", path)
+	}
+
+	return input, nil
+}
+
+// ParseJSONInput parses a JSON schema document
+func ParseJSONInput(data []byte) (*Input, error) {
+	var input Input
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&input); err != nil {
+		return nil, errors.Wrap(err, "parsing JSON")
+	}
+
+	return &input, nil
+}
+
+// ParseYAMLInput parses a YAML schema document
+func ParseYAMLInput(data []byte) (*Input, error) {
+	var input Input
+	if err := yaml.UnmarshalStrict(data, &input); err != nil {
+		return nil, errors.Wrap(err, "parsing YAML")
+	}
+
+	return &input, nil
+}
+
+// Validate checks that the input describes a schema the template can generate
+// code for. The error points at the offending table or column.
+func (i *Input) Validate() error {
+	if i.Package == "" {
+		return errors.New("missing package")
+	}
+	if !token.IsIdentifier(i.Package) {
+		return errors.Errorf("invalid package name %q", i.Package)
+	}
+	if len(i.Tables) == 0 {
+		return errors.New("no tables")
+	}
+	switch i.NullStyle {
+	case "", NullStyleSQL, NullStylePointer:
+	default:
+		return errors.Errorf("invalid null_style %q, expected %q or %q", i.NullStyle, NullStyleSQL, NullStylePointer)
+	}
+	if _, ok := dialects[i.Dialect]; i.Dialect != "" && !ok {
+		return errors.Errorf("invalid dialect %q, expected \"sqlite\", \"sqlite_returning\", \"postgres\" or \"mysql\"", i.Dialect)
+	}
+
+	tables := make(map[string]*Table, len(i.Tables))
+	for n := range i.Tables {
+		t := &i.Tables[n]
+		if t.Name == "" {
+			return errors.Errorf("table #%d: missing name", n+1)
+		}
+		if tables[t.Name] != nil {
+			return errors.Errorf("table %q: defined more than once", t.Name)
+		}
+		tables[t.Name] = t
+
+		if err := t.validate(i.NullStyle); err != nil {
+			return errors.Wrapf(err, "table %q", t.Name)
+		}
+	}
+
+	for n := range i.Tables {
+		t := &i.Tables[n]
+		if err := t.validateAssociations(tables); err != nil {
+			return errors.Wrapf(err, "table %q", t.Name)
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) validate(nullStyle string) error {
+	if !token.IsIdentifier(t.StructName()) {
+		return errors.Errorf("name does not produce a valid Go identifier (%q)", t.StructName())
+	}
+	if len(t.Columns) == 0 {
+		return errors.New("no columns")
+	}
+
+	for n := range t.Columns {
+		c := &t.Columns[n]
+		if c.Name == "" {
+			return errors.Errorf("column #%d: missing name", n+1)
+		}
+		if t.Column(c.Name) != c {
+			return errors.Errorf("column %q: defined more than once", c.Name)
+		}
+		if err := c.validate(nullStyle); err != nil {
+			return errors.Wrapf(err, "column %q", c.Name)
+		}
+	}
+
+	if t.TouchOnUpdateAll && len(t.UpdateTimestampColumns()) == 0 {
+		return errors.New("touch_on_update_all: no update timestamp columns")
+	}
+
+	for i, pk := range t.PrimaryKeyColumns() {
+		name := t.PrimaryKeyNames()[i]
+		switch {
+		case pk == nil && len(t.PrimaryKey) == 0:
+			return errors.New(`missing "id" column; set primary_key to use another column`)
+		case pk == nil:
+			return errors.Errorf("primary_key %q: unknown column", name)
+		case pk.Nullable:
+			return errors.Errorf("primary_key %q: column is nullable", name)
+		}
+		for _, other := range t.PrimaryKeyNames()[:i] {
+			if other == name {
+				return errors.Errorf("primary_key %q: listed more than once", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) validateAssociations(tables map[string]*Table) error {
+	for _, name := range t.BelongsTo {
+		if tables[string(name)] == nil {
+			return errors.Errorf("belongs_to %q: unknown table", name)
+		}
+		fk := t.ForeignKey(name)
+		if fk == nil {
+			return errors.Errorf("belongs_to %q: missing column %q", name, name.Singular()+"_id")
+		}
+		pk := tables[string(name)].PrimaryKeyColumn()
+		if pk == nil {
+			return errors.Errorf("belongs_to %q: associations need a single column primary key", name)
+		}
+		if fk.Type != pk.Type {
+			return errors.Errorf("belongs_to %q: column %q is %s, but the primary key of %q is %s", name, fk.Name, fk.Type, name, pk.Type)
+		}
+	}
+
+	if len(t.HasMany) > 0 && t.PrimaryKeyColumn() == nil {
+		return errors.Errorf("has_many %q: associations need a single column primary key", t.HasMany[0])
+	}
+	for _, name := range t.HasMany {
+		other := tables[string(name)]
+		if other == nil {
+			return errors.Errorf("has_many %q: unknown table", name)
+		}
+		if other.ForeignKey(TableName(t.Name)) == nil {
+			return errors.Errorf("has_many %q: table %q is missing column %q", name, name, t.Singular()+"_id")
+		}
+	}
+
+	return nil
+}
+
+func (c *Column) validate(nullStyle string) error {
+	if !token.IsIdentifier(c.FieldName()) {
+		return errors.Errorf("name does not produce a valid Go identifier (%q)", c.FieldName())
+	}
+	if c.Type == "" {
+		return errors.New("missing type")
+	}
+	if c.Nullable && nullStyle != NullStylePointer && !c.IsBytes() && c.NullType() == "" {
+		return errors.Errorf("no sql.Null type for %q, use the %q null_style instead", c.Type, NullStylePointer)
+	}
+	switch c.Timestamp {
+	case "", TimestampNone:
+	case TimestampCreate, TimestampUpdate:
+		if c.Type != "time.Time" {
+			return errors.Errorf("timestamp: column is %s, not time.Time", c.Type)
+		}
+	default:
+		return errors.Errorf("invalid timestamp %q, expected %q, %q or %q", c.Timestamp, TimestampCreate, TimestampUpdate, TimestampNone)
+	}
+	if c.Validations != nil {
+		if err := c.Validations.validate(c); err != nil {
+			return errors.Wrap(err, "validations")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYAMLInput(t *testing.T) {
+	input, err := ParseYAMLInput([]byte(`
+package: db
+tables:
+  - name: users
+    columns:
+      - {name: id, type: int64}
+      - {name: first_name, type: string}
+    has_many: [posts]
+  - name: posts
+    columns:
+      - {name: id, type: int64}
+      - {name: user_id, type: int64}
+    belongs_to: [users]
+`))
+	require.NoError(t, err)
+	require.NoError(t, input.Validate())
+	require.Equal(t, "db", input.Package)
+	require.Len(t, input.Tables, 2)
+	require.Equal(t, "first_name", input.Tables[0].Columns[1].Name)
+	require.Equal(t, []TableName{"users"}, input.Tables[1].BelongsTo)
+}
+
+func TestPrimaryKey(t *testing.T) {
+	input, err := ParseYAMLInput([]byte(`
+tables:
+  - name: users
+    primary_key: uuid
+  - name: memberships
+    primary_key: [user_id, group_id]
+`))
+	require.NoError(t, err)
+	require.Equal(t, PrimaryKey{"uuid"}, input.Tables[0].PrimaryKey)
+	require.Equal(t, PrimaryKey{"user_id", "group_id"}, input.Tables[1].PrimaryKey)
+
+	data, err := json.Marshal(input.Tables[0].PrimaryKey)
+	require.NoError(t, err)
+	require.JSONEq(t, `"uuid"`, string(data))
+	data, err = json.Marshal(input.Tables[1].PrimaryKey)
+	require.NoError(t, err)
+	require.JSONEq(t, `["user_id", "group_id"]`, string(data))
+}
+
+func TestParseJSONInputErrors(t *testing.T) {
+	for _, c := range []struct {
+		doc string
+		err string
+	}{
+		{
+			`{"tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}]}]}`,
+			"missing package",
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "colums": []}]}`,
+			`parsing JSON: json: unknown field "colums"`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "age"}]}]}`,
+			`table "users": column "age": missing type`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"type": "string"}]}]}`,
+			`table "users": column #2: missing name`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "name", "type": "string"}]}]}`,
+			`table "users": missing "id" column; set primary_key to use another column`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "primary_key": "uuid", "columns": [{"name": "id", "type": "int64"}]}]}`,
+			`table "users": primary_key "uuid": unknown column`,
+		},
+		{
+			`{"package": "db", "tables": [
+				{"name": "users", "primary_key": "uuid", "columns": [{"name": "uuid", "type": "string"}]},
+				{"name": "posts", "columns": [{"name": "id", "type": "int64"}, {"name": "user_id", "type": "int64"}], "belongs_to": ["users"]}
+			]}`,
+			`table "posts": belongs_to "users": column "user_id" is int64, but the primary key of "users" is string`,
+		},
+		{
+			`{"package": "db", "null_style": "maybe", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}]}]}`,
+			`invalid null_style "maybe", expected "sql" or "pointer"`,
+		},
+		{
+			`{"package": "db", "dialect": "oracle", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}]}]}`,
+			`invalid dialect "oracle", expected "sqlite", "sqlite_returning", "postgres" or "mysql"`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "key", "type": "uuid.UUID", "nullable": true}]}]}`,
+			`table "users": column "key": no sql.Null type for "uuid.UUID", use the "pointer" null_style instead`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "primary_key": 1, "columns": [{"name": "id", "type": "int64"}]}]}`,
+			`parsing JSON: primary_key must be a column name or a list of column names`,
+		},
+		{
+			`{"package": "db", "tables": [
+				{"name": "users", "primary_key": ["org_id", "name"], "columns": [{"name": "org_id", "type": "int64"}, {"name": "name", "type": "string"}], "has_many": ["posts"]},
+				{"name": "posts", "columns": [{"name": "id", "type": "int64"}, {"name": "user_id", "type": "int64"}]}
+			]}`,
+			`table "users": has_many "posts": associations need a single column primary key`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "posts", "columns": [{"name": "id", "type": "int64"}], "belongs_to": ["users"]}]}`,
+			`table "posts": belongs_to "users": unknown table`,
+		},
+		{
+			`{"package": "db", "tables": [
+				{"name": "users", "columns": [{"name": "id", "type": "int64"}], "has_many": ["posts"]},
+				{"name": "posts", "columns": [{"name": "id", "type": "int64"}]}
+			]}`,
+			`table "users": has_many "posts": table "posts" is missing column "user_id"`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "age", "type": "int", "validations": {"format": "^[0-9]+$"}}]}]}`,
+			`table "users": column "age": validations: format: only for string columns`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "name", "type": "string", "validations": {"length": {"min": 5, "max": 2}}}]}]}`,
+			`table "users": column "name": validations: length: min is larger than max`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "age", "type": "int", "validations": {"range": {"min": 0.5}}}]}]}`,
+			`table "users": column "age": validations: range: 0.5 is not an integer`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "age", "type": "uint", "validations": {"range": {"min": -1}}}]}]}`,
+			`table "users": column "age": validations: range: -1 doesn't fit in a uint`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "age", "type": "int8", "validations": {"range": {"max": 1000}}}]}]}`,
+			`table "users": column "age": validations: range: 1000 doesn't fit in a int8`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "level", "type": "uint8", "validations": {"inclusion": [1, 256]}}]}]}`,
+			`table "users": column "level": validations: inclusion: 256 doesn't fit in a uint8`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "role", "type": "string", "validations": {"inclusion": ["admin", 1]}}]}]}`,
+			`table "users": column "role": validations: inclusion: 1 is not a string`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "created_at", "type": "string", "timestamp": "create"}]}]}`,
+			`table "users": column "created_at": timestamp: column is string, not time.Time`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "columns": [{"name": "id", "type": "int64"}, {"name": "seen_at", "type": "time.Time", "timestamp": "always"}]}]}`,
+			`table "users": column "seen_at": invalid timestamp "always", expected "create", "update" or "none"`,
+		},
+		{
+			`{"package": "db", "tables": [{"name": "users", "touch_on_update_all": true, "columns": [{"name": "id", "type": "int64"}, {"name": "created_at", "type": "time.Time"}]}]}`,
+			`table "users": touch_on_update_all: no update timestamp columns`,
+		},
+	} {
+		input, err := ParseJSONInput([]byte(c.doc))
+		if err == nil {
+			err = input.Validate()
+		}
+		require.EqualError(t, err, c.err)
+	}
+}
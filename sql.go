@@ -0,0 +1,124 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sqlTable is a table definition as found in a database or migrations
+type sqlTable struct {
+	Name        string
+	Columns     []sqlColumn
+	ForeignKeys []sqlForeignKey
+}
+
+type sqlColumn struct {
+	Name    string
+	Type    string
+	NotNull bool
+
+	// PrimaryKey is the 1-based position of the column in the primary key, 0 if it's not part of it
+	PrimaryKey int
+}
+
+type sqlForeignKey struct {
+	Column string
+	Table  string
+
+	// RefColumn is empty when the foreign key references the primary key
+	RefColumn string
+}
+
+// buildTables turns table definitions into the Tables consumed by the
+// template. Associations are inferred from foreign keys that follow the
+// <singular>_id naming the template relies on; other foreign keys are left out.
+func buildTables(defs []sqlTable) (Tables, error) {
+	tables := make(Tables, 0, len(defs))
+	byName := make(map[string]int, len(defs))
+
+	for _, def := range defs {
+		table := Table{Name: def.Name}
+		var pk []sqlColumn
+		for _, col := range def.Columns {
+			if col.PrimaryKey > 0 {
+				pk = append(pk, col)
+			}
+			table.Columns = append(table.Columns, Column{
+				Name:     col.Name,
+				Type:     goType(col.Type),
+				Nullable: !col.NotNull && col.PrimaryKey == 0,
+			})
+		}
+
+		if len(pk) == 0 {
+			return nil, errors.Errorf("table %q: no primary key", def.Name)
+		}
+		sort.Slice(pk, func(i, j int) bool { return pk[i].PrimaryKey < pk[j].PrimaryKey })
+		if len(pk) > 1 || pk[0].Name != "id" {
+			for _, col := range pk {
+				table.PrimaryKey = append(table.PrimaryKey, col.Name)
+			}
+		}
+
+		byName[def.Name] = len(tables)
+		tables = append(tables, table)
+	}
+
+	for _, def := range defs {
+		for _, fk := range def.ForeignKeys {
+			i, ok := byName[fk.Table]
+			if !ok {
+				continue
+			}
+			pk := tables[i].PrimaryKeyColumn()
+			if pk == nil || (fk.RefColumn != "" && fk.RefColumn != pk.Name) {
+				continue
+			}
+			ref := TableName(fk.Table)
+			if fk.Column != ref.Singular()+"_id" {
+				continue
+			}
+
+			t := &tables[byName[def.Name]]
+			t.BelongsTo = appendTableName(t.BelongsTo, ref)
+			tables[i].HasMany = appendTableName(tables[i].HasMany, TableName(def.Name))
+		}
+	}
+
+	return tables, nil
+}
+
+func appendTableName(names []TableName, name TableName) []TableName {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+// goType maps a declared SQL type onto a Go type, following SQLite's type
+// affinity rules with additions for booleans, timestamps and PostgreSQL's
+// serial and bytea types.
+func goType(sqlType string) string {
+	t := strings.ToUpper(sqlType)
+	switch {
+	case strings.Contains(t, "INT"), strings.Contains(t, "SERIAL"):
+		return "int64"
+	case strings.Contains(t, "BOOL"):
+		return "bool"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return "string"
+	case t == "", strings.Contains(t, "BLOB"), strings.Contains(t, "BYTEA"):
+		return "[]byte"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"),
+		strings.Contains(t, "NUMERIC"), strings.Contains(t, "DECIMAL"):
+		return "float64"
+	case strings.Contains(t, "DATE"), strings.Contains(t, "TIME"):
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+)
+
+// ReflectSQLite reads the tables of a SQLite database, leaving out SQLite's
+// internal tables and the ones named in skip.
+func ReflectSQLite(ctx context.Context, db *sql.DB, skip ...string) (Tables, error) {
+	names, err := sqliteTableNames(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	var defs []sqlTable
+	for _, name := range names {
+		if skipped[name] {
+			continue
+		}
+		def, err := sqliteTable(ctx, db, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "table %q", name)
+		}
+		defs = append(defs, def)
+	}
+
+	return buildTables(defs)
+}
+
+func sqliteTableNames(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func sqliteTable(ctx context.Context, db *sql.DB, name string) (sqlTable, error) {
+	def := sqlTable{Name: name}
+
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", pk FROM pragma_table_info(?) ORDER BY cid`, name)
+	if err != nil {
+		return def, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var col sqlColumn
+		if err := rows.Scan(&col.Name, &col.Type, &col.NotNull, &col.PrimaryKey); err != nil {
+			return def, err
+		}
+		def.Columns = append(def.Columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return def, err
+	}
+
+	rows, err = db.QueryContext(ctx, `SELECT "from", "table", "to" FROM pragma_foreign_key_list(?) ORDER BY id, seq`, name)
+	if err != nil {
+		return def, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fk sqlForeignKey
+		var to sql.NullString
+		if err := rows.Scan(&fk.Column, &fk.Table, &to); err != nil {
+			return def, err
+		}
+		fk.RefColumn = to.String
+		def.ForeignKeys = append(def.ForeignKeys, fk)
+	}
+
+	return def, rows.Err()
+}
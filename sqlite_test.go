@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReflectSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE schema_migrations (version INTEGER NOT NULL PRIMARY KEY);
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			bio TEXT,
+			admin BOOLEAN NOT NULL,
+			avatar BLOB,
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE posts (
+			id INTEGER PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users (id),
+			editor_id INTEGER REFERENCES users (id),
+			score REAL
+		);
+	`)
+	require.NoError(t, err)
+
+	tables, err := ReflectSQLite(context.Background(), db, "schema_migrations")
+	require.NoError(t, err)
+	require.Equal(t, Tables{
+		{
+			Name: "users",
+			Columns: []Column{
+				{Name: "id", Type: "int64"},
+				{Name: "name", Type: "string"},
+				{Name: "bio", Type: "string", Nullable: true},
+				{Name: "admin", Type: "bool"},
+				{Name: "avatar", Type: "[]byte", Nullable: true},
+				{Name: "created_at", Type: "time.Time"},
+			},
+			HasMany: []TableName{"posts"},
+		},
+		{
+			Name: "posts",
+			Columns: []Column{
+				{Name: "id", Type: "int64"},
+				{Name: "user_id", Type: "int64"},
+				{Name: "editor_id", Type: "int64", Nullable: true},
+				{Name: "score", Type: "float64", Nullable: true},
+			},
+			BelongsTo: []TableName{"users"},
+		},
+	}, tables)
+
+	tables, err = ReflectSQLite(context.Background(), db)
+	require.NoError(t, err)
+	require.Equal(t, "schema_migrations", tables[0].Name)
+	require.Equal(t, PrimaryKey{"version"}, tables[0].PrimaryKey)
+}
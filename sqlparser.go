@@ -0,0 +1,245 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type sqlToken struct {
+	text string
+	line int
+
+	// quoted is set for identifiers written as "name", `name` or [name]
+	quoted bool
+}
+
+func (t sqlToken) isWord() bool {
+	if t.quoted {
+		return true
+	}
+	r := []rune(t.text)
+	return len(r) > 0 && (unicode.IsLetter(r[0]) || r[0] == '_')
+}
+
+// tokenizeSQL splits SQL into words, quoted identifiers, string and number
+// literals, and punctuation. Comments are dropped.
+func tokenizeSQL(src string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	line := 1
+	r := []rune(src)
+
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			start := line
+			i += 2
+			for i < len(r) && !(r[i] == '*' && i+1 < len(r) && r[i+1] == '/') {
+				if r[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i >= len(r) {
+				return nil, errors.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			start := line
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(r) {
+					return nil, errors.Errorf("line %d: unterminated %c", start, c)
+				}
+				if r[i] == end {
+					// A doubled quote is an escaped quote
+					if end != ']' && i+1 < len(r) && r[i+1] == end {
+						b.WriteRune(end)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				if r[i] == '\n' {
+					line++
+				}
+				b.WriteRune(r[i])
+				i++
+			}
+			if c == '\'' {
+				tokens = append(tokens, sqlToken{text: "'" + b.String() + "'", line: start})
+			} else {
+				tokens = append(tokens, sqlToken{text: b.String(), line: start, quoted: true})
+			}
+		case unicode.IsLetter(c) || c == '_' || unicode.IsDigit(c):
+			start := i
+			for i < len(r) && (unicode.IsLetter(r[i]) || unicode.IsDigit(r[i]) || r[i] == '_' || r[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{text: string(r[start:i]), line: line})
+		default:
+			tokens = append(tokens, sqlToken{text: string(c), line: line})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+func (p *sqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *sqlParser) peek() sqlToken {
+	if p.done() {
+		return sqlToken{line: p.lastLine()}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *sqlParser) next() sqlToken {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *sqlParser) lastLine() int {
+	if len(p.tokens) == 0 {
+		return 1
+	}
+	return p.tokens[len(p.tokens)-1].line
+}
+
+// accept consumes the next token if it's one of the given keywords or punctuation
+func (p *sqlParser) accept(texts ...string) bool {
+	t := p.peek()
+	if t.quoted {
+		return false
+	}
+	for _, text := range texts {
+		if strings.EqualFold(t.text, text) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *sqlParser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("expected %s", text)
+	}
+	return nil
+}
+
+func (p *sqlParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	if p.done() {
+		return errors.Errorf(format+", found end of file", args...)
+	}
+	return errors.Errorf(format+", found %q on line %d", append(args, t.text, t.line)...)
+}
+
+// name parses an identifier
+func (p *sqlParser) name() (string, error) {
+	if !p.peek().isWord() {
+		return "", p.errorf("expected name")
+	}
+	return p.next().text, nil
+}
+
+// qualifiedName parses an identifier with an optional schema, which is dropped
+func (p *sqlParser) qualifiedName() (string, error) {
+	name, err := p.name()
+	if err != nil {
+		return "", err
+	}
+	if p.accept(".") {
+		return p.name()
+	}
+	return name, nil
+}
+
+// nameList parses a parenthesised list of identifiers. Sort orders and
+// collations after a name are skipped, as in index definitions.
+func (p *sqlParser) nameList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		p.skipElement()
+		if p.accept(")") {
+			return names, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipParens skips a parenthesised group, including nested ones
+func (p *sqlParser) skipParens() {
+	depth := 0
+	for !p.done() {
+		switch p.next().text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth <= 0 {
+			return
+		}
+	}
+}
+
+// skipElement skips to the comma or closing parenthesis ending the current list element
+func (p *sqlParser) skipElement() {
+	for !p.done() {
+		switch p.peek().text {
+		case ",", ")", ";":
+			return
+		case "(":
+			p.skipParens()
+		default:
+			p.next()
+		}
+	}
+}
+
+// skipStatement skips past the semicolon ending the current statement
+func (p *sqlParser) skipStatement() {
+	for !p.done() && !p.accept(";") {
+		p.next()
+	}
+}
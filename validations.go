@@ -0,0 +1,211 @@
+package main
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Validations are the checks the generated Validate method runs on a
+// column's value. Values that are NULL only fail the presence check.
+type Validations struct {
+	// Presence rejects NULL, blank text, empty bytes and the zero time
+	Presence bool `json:"presence,omitempty" yaml:"presence,omitempty"`
+
+	// Length bounds the number of characters of text, or bytes of []byte
+	Length *Bounds `json:"length,omitempty" yaml:"length,omitempty"`
+
+	// Format is a regular expression text has to match
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+
+	// Inclusion lists the allowed values
+	Inclusion []interface{} `json:"inclusion,omitempty" yaml:"inclusion,omitempty"`
+
+	// Range bounds numbers
+	Range *Bounds `json:"range,omitempty" yaml:"range,omitempty"`
+
+	// Unique rejects values another row of the table already has
+	Unique bool `json:"unique,omitempty" yaml:"unique,omitempty"`
+}
+
+// Bounds is an inclusive range, open on the sides that aren't set
+type Bounds struct {
+	Min *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+func (b *Bounds) MinLiteral() string {
+	return formatNumber(*b.Min)
+}
+
+func (b *Bounds) MaxLiteral() string {
+	return formatNumber(*b.Max)
+}
+
+// validate checks the bounds can be compared with a value of the Go type
+func (b *Bounds) validate(typ string) error {
+	if b.Min == nil && b.Max == nil {
+		return errors.New("needs a min or max")
+	}
+	for _, bound := range []*float64{b.Min, b.Max} {
+		if bound == nil {
+			continue
+		}
+		if isInteger(typ) && *bound != math.Trunc(*bound) {
+			return errors.Errorf("%s is not an integer", formatNumber(*bound))
+		}
+		if !fits(typ, *bound) {
+			return errors.Errorf("%s doesn't fit in a %s", formatNumber(*bound), typ)
+		}
+	}
+	if b.Min != nil && b.Max != nil && *b.Min > *b.Max {
+		return errors.New("min is larger than max")
+	}
+	return nil
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// ValidatesValue is true when Validate checks more than whether the column is NULL
+func (c *Column) ValidatesValue() bool {
+	v := c.Validations
+	if v == nil {
+		return false
+	}
+	blankable := c.Type == "string" || c.IsBytes() || c.Type == "time.Time"
+	return v.Presence && blankable || v.Length != nil || v.Format != "" || len(v.Inclusion) > 0 || v.Range != nil || v.Unique
+}
+
+// InclusionLiterals are the allowed values as Go literals
+func (c *Column) InclusionLiterals() []string {
+	literals := make([]string, len(c.Validations.Inclusion))
+	for i, value := range c.Validations.Inclusion {
+		switch value := value.(type) {
+		case string:
+			literals[i] = strconv.Quote(value)
+		case float64:
+			literals[i] = formatNumber(value)
+		case int:
+			literals[i] = strconv.Itoa(value)
+		}
+	}
+	return literals
+}
+
+func isNumber(typ string) bool {
+	switch typ {
+	case "float32", "float64":
+		return true
+	default:
+		return isInteger(typ)
+	}
+}
+
+func isInteger(typ string) bool {
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		return true
+	default:
+		return false
+	}
+}
+
+// fits is true when the number can be held by the Go number type, so it can
+// be a constant of it in the generated code. int and uint are taken to be
+// 64 bits.
+func fits(typ string, f float64) bool {
+	switch typ {
+	case "float32":
+		return math.Abs(f) <= math.MaxFloat32
+	case "float64":
+		return true
+	}
+	bits := 64
+	switch typ {
+	case "int8", "uint8", "byte":
+		bits = 8
+	case "int16", "uint16":
+		bits = 16
+	case "int32", "uint32":
+		bits = 32
+	}
+	if strings.HasPrefix(typ, "uint") || typ == "byte" {
+		return f >= 0 && f < math.Ldexp(1, bits)
+	}
+	return f >= -math.Ldexp(1, bits-1) && f < math.Ldexp(1, bits-1)
+}
+
+func (v *Validations) validate(c *Column) error {
+	text := c.Type == "string"
+	if v.Presence && !c.Nullable && !text && !c.IsBytes() && c.Type != "time.Time" {
+		return errors.Errorf("presence: a %s column that isn't nullable is always present", c.Type)
+	}
+	if v.Length != nil {
+		if !text && !c.IsBytes() {
+			return errors.New("length: only for string and []byte columns")
+		}
+		if err := v.Length.validate("int"); err != nil {
+			return errors.Wrap(err, "length")
+		}
+	}
+	if v.Format != "" {
+		if !text {
+			return errors.New("format: only for string columns")
+		}
+		if _, err := regexp.Compile(v.Format); err != nil {
+			return errors.Wrap(err, "format")
+		}
+	}
+	for _, value := range v.Inclusion {
+		switch value := value.(type) {
+		case string:
+			if !text {
+				return errors.Errorf("inclusion: %q is not a %s", value, c.Type)
+			}
+		case float64:
+			if !isNumber(c.Type) || isInteger(c.Type) && value != math.Trunc(value) {
+				return errors.Errorf("inclusion: %s is not a %s", formatNumber(value), c.Type)
+			}
+			if !fits(c.Type, value) {
+				return errors.Errorf("inclusion: %s doesn't fit in a %s", formatNumber(value), c.Type)
+			}
+		case int:
+			if !isNumber(c.Type) {
+				return errors.Errorf("inclusion: %d is not a %s", value, c.Type)
+			}
+			if !fits(c.Type, float64(value)) {
+				return errors.Errorf("inclusion: %d doesn't fit in a %s", value, c.Type)
+			}
+		default:
+			return errors.Errorf("inclusion: only strings and numbers are supported, not %v", value)
+		}
+	}
+	if v.Range != nil {
+		if !isNumber(c.Type) {
+			return errors.New("range: only for number columns")
+		}
+		if err := v.Range.validate(c.Type); err != nil {
+			return errors.Wrap(err, "range")
+		}
+	}
+	return nil
+}
+
+// validationImports lists the packages the validations of the column need
+func (c *Column) validationImports() []string {
+	var imports []string
+	if v := c.Validations; v != nil {
+		if v.Format != "" {
+			imports = append(imports, "regexp")
+		}
+		if v.Length != nil && c.Type == "string" {
+			imports = append(imports, "unicode/utf8")
+		}
+	}
+	return imports
+}